@@ -0,0 +1,105 @@
+package glint
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/droqsic/glint/internal/ansi"
+	"github.com/droqsic/glint/internal/core"
+	"github.com/droqsic/glint/internal/platform"
+	"github.com/droqsic/probe"
+)
+
+type Background int8 // Background describes the brightness of the terminal background.
+
+const (
+	BackgroundAuto  Background = iota // BackgroundAuto detects the background from the terminal
+	BackgroundDark                    // BackgroundDark indicates a dark background
+	BackgroundLight                   // BackgroundLight indicates a light background
+)
+
+// backgroundQueryTimeout bounds how long background detection waits for the terminal to answer.
+const backgroundQueryTimeout = 100 * time.Millisecond
+
+var (
+	background      Background // background stores the detected terminal background
+	backgroundOnce  sync.Once  // backgroundOnce ensures background detection runs only once
+	forceBackground Background // forceBackground overrides detection when it is not BackgroundAuto
+	backgroundMutex sync.Mutex // backgroundMutex protects concurrent access to the background state
+)
+
+// String returns a human-readable description of the background.
+func (b Background) String() string {
+	switch b {
+	case BackgroundDark:
+		return "dark"
+	case BackgroundLight:
+		return "light"
+	default:
+		return "auto"
+	}
+}
+
+// DetectBackground determines whether the terminal background is dark or light.
+// It asks the terminal for its background color with an OSC 11 query when stdout is a terminal,
+// falls back to the COLORFGBG environment variable, and assumes a dark background if neither answers.
+// A value set with SetBackground takes precedence. The result is cached after the first call. This function is thread-safe.
+func DetectBackground() Background {
+	backgroundMutex.Lock()
+	defer backgroundMutex.Unlock()
+
+	if forceBackground != BackgroundAuto {
+		return forceBackground
+	}
+
+	backgroundOnce.Do(func() {
+		background = detectBackground()
+	})
+	return background
+}
+
+// HasDarkBackground reports whether the terminal background is dark, see DetectBackground.
+func HasDarkBackground() bool {
+	return DetectBackground() == BackgroundDark
+}
+
+// SetBackground overrides background detection, for example from a --theme flag.
+// BackgroundAuto clears the override and detects the background again on the next call.
+func SetBackground(b Background) {
+	backgroundMutex.Lock()
+	defer backgroundMutex.Unlock()
+
+	forceBackground = b
+	if b == BackgroundAuto {
+		backgroundOnce = sync.Once{}
+	}
+}
+
+// detectBackground runs the detection steps in order of reliability.
+func detectBackground() Background {
+	if f := core.ActiveFake(); f != nil {
+		if b := Background(f.Background); b != BackgroundAuto {
+			return b
+		}
+	} else if probe.IsTerminal(os.Stdout.Fd()) {
+		if reply, err := platform.QueryTerminal("\x1b]11;?\x1b\\", backgroundQueryTimeout); err == nil {
+			if color, ok := ansi.ParseColorReport(reply); ok {
+				return backgroundFor(color.IsDark())
+			}
+		}
+	}
+
+	if dark, ok := core.BackgroundFromEnv(); ok {
+		return backgroundFor(dark)
+	}
+	return BackgroundDark
+}
+
+// backgroundFor converts a brightness check into a Background.
+func backgroundFor(dark bool) Background {
+	if dark {
+		return BackgroundDark
+	}
+	return BackgroundLight
+}
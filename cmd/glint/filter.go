@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/droqsic/glint"
+)
+
+// runStrip implements the strip command.
+func runStrip(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("strip", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	return exitCode(stderr, glint.ToPlain(stdin, stdout))
+}
+
+// runConvert implements the convert command.
+func runConvert(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	to := fs.String("to", "", "downsample colors to this level: none, 16, 256 or truecolor (default: detected level of stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	level := glint.ColorLevel()
+	if *to != "" {
+		var err error
+		if level, err = glint.ParseLevel(*to); err != nil {
+			fmt.Fprintln(stderr, "glint:", err)
+			return 2
+		}
+	}
+	return exitCode(stderr, glint.ToLevel(stdin, stdout, level))
+}
+
+// runHTML implements the html command.
+func runHTML(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("html", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	classes := fs.Bool("classes", false, "use CSS classes instead of inline styles")
+	palette := fs.String("palette", "xterm", "palette for the basic colors: xterm, solarized or dracula")
+	fragment := fs.Bool("fragment", false, "omit the surrounding <pre> element")
+	standalone := fs.Bool("standalone", false, "write a complete HTML document, including the stylesheet with --classes")
+	title := fs.String("title", "", "document title with --standalone")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	opts := glint.HTMLOptions{Classes: *classes, Fragment: *fragment}
+	var ok bool
+	if opts.Palette, ok = glint.PaletteNamed(*palette); !ok {
+		fmt.Fprintf(stderr, "glint: unknown palette %q\n", *palette)
+		return 2
+	}
+
+	w := bufio.NewWriter(stdout)
+	if *standalone {
+		fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", html.EscapeString(*title))
+		if opts.Classes {
+			fmt.Fprintf(w, "<style>\n%s</style>\n", glint.HTMLStylesheet(opts))
+		}
+		fmt.Fprint(w, "</head>\n<body>\n")
+	}
+	if err := glint.ToHTML(stdin, w, opts); err != nil {
+		return exitCode(stderr, err)
+	}
+	if *standalone {
+		fmt.Fprint(w, "</body>\n</html>\n")
+	}
+	return exitCode(stderr, w.Flush())
+}
+
+// runSVG implements the svg command.
+func runSVG(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("svg", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	levelFlag := fs.String("level", "truecolor", "color level to emulate: none, 16, 256 or truecolor")
+	palette := fs.String("palette", "xterm", "palette for the basic colors: xterm, solarized or dracula")
+	columns := fs.Int("columns", 0, "wrap lines at this many columns, 0 fits the longest line")
+	fontSize := fs.Float64("font-size", 0, "font size in pixels (default 14)")
+	title := fs.String("title", "", "window title")
+	noWindow := fs.Bool("no-window", false, "omit the window frame and title bar")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	opts := glint.SVGOptions{Columns: *columns, FontSize: *fontSize, Title: *title, NoWindow: *noWindow}
+	var err error
+	if opts.Level, err = glint.ParseLevel(*levelFlag); err != nil {
+		fmt.Fprintln(stderr, "glint:", err)
+		return 2
+	}
+	opts.Monochrome = opts.Level == glint.LevelNone
+	var ok bool
+	if opts.Palette, ok = glint.PaletteNamed(*palette); !ok {
+		fmt.Fprintf(stderr, "glint: unknown palette %q\n", *palette)
+		return 2
+	}
+	return exitCode(stderr, glint.ToSVG(stdin, stdout, opts))
+}
+
+// exitCode prints err, if any, and returns the matching exit code.
+func exitCode(stderr io.Writer, err error) int {
+	if err != nil {
+		fmt.Fprintln(stderr, "glint:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/ansi"
+	"github.com/droqsic/glint/internal/core"
+	"github.com/droqsic/glint/internal/platform"
+	"github.com/droqsic/probe"
+)
+
+// queryTimeout bounds how long --query waits for the terminal to answer.
+const queryTimeout = 500 * time.Millisecond
+
+// report is everything glint knows about the current terminal.
+type report struct {
+	Version     string            `json:"version"`               // Version is the glint library version
+	Supported   bool              `json:"supported"`             // Supported is the result of glint.ColorSupport
+	Level       string            `json:"level"`                 // Level is the short name of glint.ColorLevel
+	Rule        string            `json:"rule"`                  // Rule names the setting that decided the level
+	Stderr      detection         `json:"stderr"`                // Stderr is the outcome for stderr, often still a terminal when stdout is redirected
+	Environment detection         `json:"environment"`           // Environment is the outcome the environment gives a terminal, whatever stdout is
+	Config      configReport      `json:"config"`                // Config describes the config file
+	TTY         ttyReport         `json:"tty"`                   // TTY tells which standard streams are terminals
+	Size        *sizeReport       `json:"size"`                  // Size is the terminal size, nil if unknown
+	Multiplexer string            `json:"multiplexer,omitempty"` // Multiplexer is tmux, screen or zellij
+	Embedding   string            `json:"embedding,omitempty"`   // Embedding names the editor hosting the terminal, such as emacs-shell
+	CI          string            `json:"ci,omitempty"`          // CI names the CI service
+	Env         map[string]string `json:"env"`                   // Env holds the relevant variables that are set
+	Query       *queryReport      `json:"query,omitempty"`       // Query holds the answers to terminal queries, with --query
+}
+
+// detection is the outcome of color detection for one destination.
+type detection struct {
+	Supported bool   `json:"supported"`      // Supported reports whether colors are used
+	Level     string `json:"level"`          // Level is the short name of the level
+	Rule      string `json:"rule,omitempty"` // Rule names the setting that decided the level, where it is known
+}
+
+// configReport describes the config file.
+type configReport struct {
+	Path   string `json:"path"`   // Path is the location glint reads
+	Exists bool   `json:"exists"` // Exists tells whether the file is present
+}
+
+// ttyReport tells which standard streams are terminals.
+type ttyReport struct {
+	Stdin  bool `json:"stdin"`
+	Stdout bool `json:"stdout"`
+	Stderr bool `json:"stderr"`
+}
+
+// sizeReport is the terminal size in cells.
+type sizeReport struct {
+	Columns int `json:"columns"`
+	Rows    int `json:"rows"`
+}
+
+// queryReport holds the answers to terminal queries. Fields are empty when the terminal doesn't answer.
+type queryReport struct {
+	Foreground string `json:"foreground,omitempty"` // Foreground is the default foreground color, from OSC 10
+	Background string `json:"background,omitempty"` // Background is the default background color, from OSC 11
+	Theme      string `json:"theme,omitempty"`      // Theme is dark or light, from the background color
+	Terminal   string `json:"terminal,omitempty"`   // Terminal is the name and version reported to XTVERSION
+	Error      string `json:"error,omitempty"`      // Error explains why the terminal could not be queried
+}
+
+// runInfo implements the info command.
+func runInfo(args []string, _ io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("info", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "write the report as JSON")
+	query := fs.Bool("query", false, "ask the terminal for its colors and version")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	r := gather(*query)
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			fmt.Fprintln(stderr, "glint:", err)
+			return 1
+		}
+		return 0
+	}
+
+	writeReport(stdout, r)
+	return 0
+}
+
+// gather collects the report, querying the terminal if query is set.
+func gather(query bool) report {
+	e := glint.Explain()
+	env := glint.DetectWithEnv(os.Environ())
+	r := report{
+		Version:     glint.Version,
+		Supported:   e.Supported,
+		Level:       glint.LevelName(e.Level),
+		Rule:        e.Rule,
+		Stderr:      detection{Supported: glint.ColorSupportFor(os.Stderr), Level: glint.LevelName(glint.ColorLevelFor(os.Stderr))},
+		Environment: detection{Supported: env.Supported, Level: glint.LevelName(env.Level), Rule: env.Rule},
+		Config:      configReport{Path: glint.ConfigPath()},
+		TTY:         ttyReport{Stdin: isTerminal(os.Stdin), Stdout: isTerminal(os.Stdout), Stderr: isTerminal(os.Stderr)},
+		Multiplexer: core.DetectMultiplexer(os.Getenv),
+		Embedding:   core.DetectEmbedding(os.Getenv),
+		CI:          core.DetectCI(os.Getenv),
+		Env:         make(map[string]string),
+	}
+
+	if _, err := os.Stat(r.Config.Path); err == nil {
+		r.Config.Exists = true
+	}
+
+	for _, f := range []*os.File{os.Stdout, os.Stderr, os.Stdin} {
+		if columns, rows, err := platform.TerminalSize(f.Fd()); err == nil && columns > 0 {
+			r.Size = &sizeReport{Columns: columns, Rows: rows}
+			break
+		}
+	}
+
+	for _, key := range core.KnownKeys() {
+		if value, ok := os.LookupEnv(key); ok {
+			r.Env[key] = value
+		}
+	}
+
+	if query {
+		r.Query = queryTerminal()
+	}
+	return r
+}
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	return probe.IsTerminal(f.Fd()) || probe.IsCygwinTerminal(f.Fd())
+}
+
+// queryTerminal asks the terminal for its default colors and version in one round trip.
+func queryTerminal() *queryReport {
+	q := &queryReport{}
+	reply, err := platform.QueryTerminal("\x1b]10;?\x1b\\\x1b]11;?\x1b\\\x1b[>0q", queryTimeout)
+	if err != nil && !errors.Is(err, platform.ErrQueryTimeout) {
+		q.Error = err.Error()
+		return q
+	}
+
+	for s := reply; s != ""; {
+		tok, ok := ansi.Next(s)
+		if !ok {
+			break
+		}
+		s = s[len(tok.Raw):]
+
+		switch data := tok.Data(); {
+		case tok.Kind == ansi.TokenOSC && strings.HasPrefix(data, "10;"):
+			if c, ok := ansi.ParseColorReport(tok.Raw); ok {
+				q.Foreground = hexColor(c)
+			}
+		case tok.Kind == ansi.TokenOSC && strings.HasPrefix(data, "11;"):
+			if c, ok := ansi.ParseColorReport(tok.Raw); ok {
+				q.Background = hexColor(c)
+				q.Theme = "light"
+				if c.IsDark() {
+					q.Theme = "dark"
+				}
+			}
+		case tok.Kind == ansi.TokenString && strings.HasPrefix(data, "P>|"):
+			q.Terminal = data[len("P>|"):]
+		}
+	}
+
+	if err != nil && *q == (queryReport{}) {
+		q.Error = err.Error()
+	}
+	return q
+}
+
+// hexColor formats an RGB color as #rrggbb.
+func hexColor(c core.Color) string {
+	r, g, b := c.RGB()
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// writeReport writes r as aligned sections of text.
+func writeReport(out io.Writer, r report) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	row := func(name, value string) {
+		fmt.Fprintf(w, "  %s\t%s\n", name, value)
+	}
+
+	fmt.Fprintf(w, "glint %s\n\nColor\n", r.Version)
+	row("supported", yesNo(r.Supported))
+	row("level", r.Level)
+	row("rule", r.Rule)
+	row("stderr", r.Stderr.String())
+	row("environment", r.Environment.String())
+	config := r.Config.Path
+	if !r.Config.Exists {
+		config += " (not found)"
+	}
+	row("config", config)
+
+	fmt.Fprintln(w, "\nTerminal")
+	row("stdin", ttyName(r.TTY.Stdin))
+	row("stdout", ttyName(r.TTY.Stdout))
+	row("stderr", ttyName(r.TTY.Stderr))
+	if r.Size != nil {
+		row("size", fmt.Sprintf("%dx%d", r.Size.Columns, r.Size.Rows))
+	} else {
+		row("size", "unknown")
+	}
+	row("multiplexer", orNone(r.Multiplexer))
+	row("embedding", orNone(r.Embedding))
+	row("ci", orNone(r.CI))
+
+	if q := r.Query; q != nil {
+		fmt.Fprintln(w, "\nQuery")
+		if q.Error != "" {
+			row("error", q.Error)
+		}
+		row("foreground", orNone(q.Foreground))
+		row("background", orNone(q.Background))
+		row("theme", orNone(q.Theme))
+		row("terminal", orNone(q.Terminal))
+	}
+
+	fmt.Fprintln(w, "\nEnvironment")
+	for _, key := range core.KnownKeys() {
+		if value, ok := r.Env[key]; ok {
+			row(key, fmt.Sprintf("%q", value))
+		} else {
+			row(key, "(unset)")
+		}
+	}
+}
+
+// String formats d for the text report, such as "256 (TERM=xterm-256color)".
+func (d detection) String() string {
+	if d.Rule == "" {
+		return d.Level
+	}
+	return d.Level + " (" + d.Rule + ")"
+}
+
+// yesNo formats a boolean for the text report.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// ttyName describes whether a stream is a terminal.
+func ttyName(tty bool) string {
+	if tty {
+		return "terminal"
+	}
+	return "not a terminal"
+}
+
+// orNone replaces an empty value with "none".
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
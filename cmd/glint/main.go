@@ -0,0 +1,77 @@
+// Command glint reports what glint detects about the current terminal, so the report can be pasted into bug reports,
+// and provides filters built on glint's parser for use in shell pipelines.
+//
+// Usage:
+//
+//	glint [info] [--json] [--query]
+//	glint palette [--level none|16|256|truecolor]
+//	glint strip < colored.log
+//	glint convert --to 256 < truecolor.log
+//	glint html [--classes] [--standalone] < colored.log
+//	glint svg [--columns 80] [--title title] < colored.log
+//	glint run [--match | --to level] -- command [args...]
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// command is a subcommand of the glint tool. It returns the process exit code.
+type command struct {
+	name    string                                                             // name is the word selecting the command
+	summary string                                                             // summary is shown in the usage message
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) int // run executes the command
+}
+
+// commands lists the subcommands in the order the usage message shows them.
+var commands = []command{
+	{"info", "report color support, the deciding rule and terminal details (default)", runInfo},
+	{"palette", "render color test patterns and text attributes at a chosen --level", runPalette},
+	{"strip", "remove all escape sequences from stdin", runStrip},
+	{"convert", "downsample the colors on stdin --to none, 16, 256 or truecolor", runConvert},
+	{"html", "render colored output from stdin as HTML", runHTML},
+	{"svg", "render colored output from stdin as an SVG terminal window", runSVG},
+	{"run", "run a command under a pseudo-terminal and copy its colored output (Linux)", runRun},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run dispatches to the subcommand named by the first argument, or to info if there is none.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help" {
+		return runInfo(args, stdin, stdout, stderr)
+	}
+
+	switch args[0] {
+	case "help", "-h", "--help":
+		usage(stdout)
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "glint: unknown command %q\n\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+// usage writes the list of subcommands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: glint <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "glint <command> -h" for the flags of a command.`)
+}
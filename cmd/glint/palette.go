@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/droqsic/glint"
+)
+
+// attributes lists the text attributes shown by the palette command with their SGR parameters.
+var attributes = []struct {
+	name string // name labels the sample
+	sgr  string // sgr is the parameter enabling the attribute
+}{
+	{"bold", "1"},
+	{"dim", "2"},
+	{"italic", "3"},
+	{"underline", "4"},
+	{"blink", "5"},
+	{"reverse", "7"},
+	{"hidden", "8"},
+	{"strikethrough", "9"},
+	{"overline", "53"},
+}
+
+// runPalette implements the palette command.
+func runPalette(args []string, _ io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("palette", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	levelFlag := fs.String("level", "", "render at this level: none, 16, 256 or truecolor (default: detected level)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	level, source := glint.ColorLevel(), "detected, "+glint.Explain().Rule
+	if *levelFlag != "" {
+		var err error
+		if level, err = glint.ParseLevel(*levelFlag); err != nil {
+			fmt.Fprintln(stderr, "glint:", err)
+			return 2
+		}
+		source = "--level"
+	}
+
+	w := bufio.NewWriter(stdout)
+	fmt.Fprintf(w, "Rendering at level %s (%s)\n", glint.LevelName(level), source)
+	if level == glint.LevelNone {
+		fmt.Fprintln(w, "Colors and attributes are disabled at this level, use --level to choose another.")
+	}
+	writePalette(w, level)
+	if err := w.Flush(); err != nil {
+		fmt.Fprintln(stderr, "glint:", err)
+		return 1
+	}
+	return 0
+}
+
+// writePalette writes the test patterns, with every color downsampled to level.
+func writePalette(w io.Writer, level glint.Level) {
+	reset := glint.Reset
+	if level == glint.LevelNone {
+		reset = ""
+	}
+
+	// swatch writes text on a background color, in black or white depending on the brightness of the color.
+	swatch := func(bg glint.Color, text string) {
+		fg := glint.ANSI(15)
+		if r, g, b := bg.Resolve(level).RGB(); 299*int(r)+587*int(g)+114*int(b) > 128000 {
+			fg = glint.ANSI(0)
+		}
+		fmt.Fprint(w, fg.Resolve(level).Sequence(false)+bg.Resolve(level).Sequence(true)+text+reset)
+	}
+
+	fmt.Fprintln(w, "\nBasic colors")
+	for row := 0; row < 2; row++ {
+		fmt.Fprint(w, "  ")
+		for i := row * 8; i < row*8+8; i++ {
+			swatch(glint.ANSI(uint8(i)), fmt.Sprintf(" %2d ", i))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "\n256 color cube")
+	for g := 0; g < 6; g++ {
+		fmt.Fprint(w, "  ")
+		for r := 0; r < 6; r++ {
+			if r > 0 {
+				fmt.Fprint(w, " ")
+			}
+			for b := 0; b < 6; b++ {
+				swatch(glint.ANSI256(uint8(16+36*r+6*g+b)), "  ")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "\nGrayscale ramp")
+	fmt.Fprint(w, "  ")
+	for i := 232; i < 256; i++ {
+		swatch(glint.ANSI256(uint8(i)), "   ")
+	}
+	fmt.Fprintln(w)
+
+	const gradientWidth = 72
+	fmt.Fprintln(w, "\nTruecolor gradient")
+	fmt.Fprint(w, "  ")
+	for i := 0; i < gradientWidth; i++ {
+		swatch(hue(float64(i)*360/gradientWidth), " ")
+	}
+	fmt.Fprint(w, "\n  ")
+	for i := 0; i < gradientWidth; i++ {
+		v := uint8(i * 255 / (gradientWidth - 1))
+		swatch(glint.RGB(v, v, v), " ")
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "\nAttributes")
+	fmt.Fprint(w, "  ")
+	for _, attr := range attributes {
+		if level == glint.LevelNone {
+			fmt.Fprint(w, attr.name+" ")
+			continue
+		}
+		fmt.Fprint(w, "\x1b["+attr.sgr+"m"+attr.name+reset+" ")
+	}
+	fmt.Fprintln(w)
+}
+
+// hue returns the fully saturated color at the given hue in degrees.
+func hue(degrees float64) glint.Color {
+	channel := func(n float64) uint8 {
+		k := math.Mod(n+degrees/60, 6)
+		return uint8(math.Round(255 * (1 - max(0, min(k, 4-k, 1)))))
+	}
+	return glint.RGB(channel(5), channel(3), channel(1))
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/platform"
+	"github.com/droqsic/glint/pty"
+)
+
+// runRun implements the run command.
+func runRun(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	match := fs.Bool("match", false, "downsample or strip the child's colors to the level detected for stdout")
+	to := fs.String("to", "", "downsample the child's colors to this level: none, 16, 256 or truecolor")
+	columns := fs.Int("columns", 0, "terminal width when stdout is not a terminal (default 80)")
+	rows := fs.Int("rows", 0, "terminal height when stdout is not a terminal (default 24)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: glint run [flags] -- command [args...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	convert := func(r io.Reader, w io.Writer) error {
+		_, err := io.Copy(w, r)
+		return err
+	}
+	if *match || *to != "" {
+		level := glint.ColorLevel()
+		if *to != "" {
+			var err error
+			if level, err = glint.ParseLevel(*to); err != nil {
+				fmt.Fprintln(stderr, "glint:", err)
+				return 2
+			}
+		}
+		convert = func(r io.Reader, w io.Writer) error {
+			return glint.ToLevel(r, w, level)
+		}
+	}
+
+	cmd := exec.Command(fs.Arg(0), fs.Args()[1:]...)
+	cmd.Env = childEnv(os.Environ())
+
+	size := pty.Size{Columns: 80, Rows: 24}
+	out, outIsFile := stdout.(*os.File)
+	tty := outIsFile && isTerminal(out)
+	if tty {
+		if c, r, err := platform.TerminalSize(out.Fd()); err == nil && c > 0 {
+			size = pty.Size{Columns: c, Rows: r}
+		}
+	}
+	if *columns > 0 {
+		size.Columns = *columns
+	}
+	if *rows > 0 {
+		size.Rows = *rows
+	}
+
+	// Captured output keeps plain newlines instead of the CR LF a terminal would receive.
+	term, err := pty.StartWith(cmd, pty.Options{Size: size, RawOutput: !tty})
+	if err != nil {
+		fmt.Fprintln(stderr, "glint:", err)
+		if errors.Is(err, exec.ErrNotFound) {
+			return 127
+		}
+		return 1
+	}
+	defer term.Close()
+
+	if tty && *columns == 0 && *rows == 0 {
+		defer term.TrackSize(out)()
+	}
+
+	// Keystrokes are forwarded when stdin is a terminal. Piped input is not, since the child could not tell its end.
+	if in, ok := stdin.(*os.File); ok && isTerminal(in) {
+		if restore, err := pty.MakeRaw(in); err == nil {
+			defer restore()
+		}
+		go io.Copy(term, in)
+	}
+
+	copyErr := convert(term, stdout)
+	waitErr := cmd.Wait()
+	if copyErr != nil {
+		fmt.Fprintln(stderr, "glint:", copyErr)
+	}
+	return childExitCode(cmd, waitErr, stderr)
+}
+
+// childEnv returns env with TERM set to a color terminal if it is unset or dumb, since the child is given a terminal.
+func childEnv(env []string) []string {
+	for i, kv := range env {
+		if value, ok := strings.CutPrefix(kv, "TERM="); ok {
+			if value != "" && value != "dumb" {
+				return env
+			}
+			env = append(env[:i:i], env[i+1:]...)
+			break
+		}
+	}
+	return append(env, "TERM=xterm-256color")
+}
+
+// childExitCode returns the exit code of the child, or 128 plus the signal number if a signal ended it.
+func childExitCode(cmd *exec.Cmd, err error, stderr io.Writer) int {
+	if cmd.ProcessState == nil {
+		fmt.Fprintln(stderr, "glint:", err)
+		return 1
+	}
+	if code := cmd.ProcessState.ExitCode(); code >= 0 {
+		return code
+	}
+	if status, ok := cmd.ProcessState.Sys().(interface {
+		Signaled() bool
+		Signal() syscall.Signal
+	}); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return 1
+}
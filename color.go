@@ -0,0 +1,100 @@
+package glint
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/droqsic/glint/internal/ansi"
+	"github.com/droqsic/glint/internal/core"
+)
+
+const Reset = ansi.Reset // Reset is the escape sequence that restores the default colors and attributes.
+
+// TerminalColor is a color that can be displayed on a terminal.
+// It is implemented by Color and AdaptiveColor.
+type TerminalColor interface {
+	// Resolve returns the concrete color to display on a terminal with the given color level.
+	Resolve(level Level) Color
+}
+
+// Color is a single terminal color: one of the 16 basic colors, an entry of the 256 color palette, or an RGB color.
+// The zero value is the terminal's default color.
+type Color struct {
+	value core.Color
+}
+
+// ANSI returns one of the 16 basic colors: 0-7 are black, red, green, yellow, blue, magenta, cyan and white, 8-15 their bright variants.
+func ANSI(index uint8) Color {
+	return Color{core.Color{Kind: core.ColorANSI, Index: index & 15}}
+}
+
+// ANSI256 returns an entry of the 256 color palette.
+func ANSI256(index uint8) Color {
+	return Color{core.Color{Kind: core.ColorANSI256, Index: index}}
+}
+
+// RGB returns a 24-bit color.
+func RGB(r, g, b uint8) Color {
+	return Color{core.Color{Kind: core.ColorRGB, R: r, G: g, B: b}}
+}
+
+// Hex returns the color described by a CSS hex string in the form "#rgb" or "#rrggbb", with or without the hash.
+// It returns the default color if s is malformed.
+func Hex(s string) Color {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return Color{}
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return Color{}
+	}
+	return RGB(uint8(v>>16), uint8(v>>8), uint8(v))
+}
+
+// IsDefault reports whether c is the terminal's default color.
+func (c Color) IsDefault() bool {
+	return c.value.IsDefault()
+}
+
+// Resolve returns the closest color a terminal with the given color level can display.
+func (c Color) Resolve(level Level) Color {
+	return Color{c.value.Convert(level)}
+}
+
+// Sequence returns the escape sequence selecting c as the foreground, or the background if background is set.
+// It returns an empty string for the default color. The color is used as is, see Resolve to downsample it first.
+func (c Color) Sequence(background bool) string {
+	if c.IsDefault() {
+		return ""
+	}
+	return "\x1b[" + c.value.Params(background) + "m"
+}
+
+// RGB returns the red, green and blue components of c. Basic colors use the xterm defaults.
+func (c Color) RGB() (r, g, b uint8) {
+	return c.value.RGB()
+}
+
+// AdaptiveColor holds one color for light backgrounds and one for dark backgrounds.
+// The variant is chosen by the detected background, see DetectBackground.
+type AdaptiveColor struct {
+	Light Color // Light is used on light backgrounds
+	Dark  Color // Dark is used on dark backgrounds
+}
+
+// Resolve picks the variant matching the terminal background and downsamples it to level.
+func (a AdaptiveColor) Resolve(level Level) Color {
+	if HasDarkBackground() {
+		return a.Dark.Resolve(level)
+	}
+	return a.Light.Resolve(level)
+}
+
+// Color returns the variant matching the terminal background, downsampled to ColorLevel().
+func (a AdaptiveColor) Color() Color {
+	return a.Resolve(ColorLevel())
+}
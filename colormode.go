@@ -0,0 +1,95 @@
+package glint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/droqsic/glint/internal/core"
+)
+
+type ColorMode int8 // ColorMode is the value of a --color command-line flag.
+
+const (
+	ColorModeAuto   ColorMode = iota // ColorModeAuto detects color support automatically
+	ColorModeAlways                  // ColorModeAlways enables color at the detected level, at least 16 colors
+	ColorModeNever                   // ColorModeNever disables color
+	ColorMode16                      // ColorMode16 enables basic ANSI colors
+	ColorMode256                     // ColorMode256 enables the 256 color palette
+	ColorModeTrue                    // ColorModeTrue enables 24-bit truecolor
+)
+
+// String returns the flag value of the mode.
+func (m ColorMode) String() string {
+	switch m {
+	case ColorModeAlways:
+		return "always"
+	case ColorModeNever:
+		return "never"
+	case ColorMode16:
+		return "16"
+	case ColorMode256:
+		return "256"
+	case ColorModeTrue:
+		return "truecolor"
+	default:
+		return "auto"
+	}
+}
+
+// Set parses a flag value, implementing flag.Value. Values are case-insensitive:
+// auto, always, never, 16, 256 and truecolor, with the aliases yes/force, no/none and 24bit.
+// An empty value is treated as always, so a bare --color works with flag packages that allow it.
+func (m *ColorMode) Set(value string) error {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "auto", "tty", "if-tty":
+		*m = ColorModeAuto
+	case "", "always", "yes", "force", "on":
+		*m = ColorModeAlways
+	case "never", "no", "none", "off":
+		*m = ColorModeNever
+	case "16", "ansi":
+		*m = ColorMode16
+	case "256", "ansi256":
+		*m = ColorMode256
+	case "truecolor", "24bit", "16m":
+		*m = ColorModeTrue
+	default:
+		return fmt.Errorf("invalid color mode %q: must be auto, always, never, 16, 256 or truecolor", value)
+	}
+	return nil
+}
+
+// Type returns the name of the value type, as required by the pflag.Value interface.
+func (m *ColorMode) Type() string {
+	return "color"
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting the same values as Set.
+func (m *ColorMode) UnmarshalText(text []byte) error {
+	return m.Set(string(text))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m ColorMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// Apply configures color detection for the mode: auto calls ResetColor, always and never call ForceColor,
+// and the explicit levels call ForceLevel. NO_COLOR keeps precedence over every mode but never, so
+// --color=always can't re-enable colors a user disabled globally.
+func (m ColorMode) Apply() {
+	switch m {
+	case ColorModeAlways:
+		ForceColor(true)
+	case ColorModeNever:
+		ForceColor(false)
+	case ColorMode16:
+		ForceLevel(core.Level16)
+	case ColorMode256:
+		ForceLevel(core.Level256)
+	case ColorModeTrue:
+		ForceLevel(core.LevelTrue)
+	default:
+		ResetColor()
+	}
+}
@@ -0,0 +1,30 @@
+package glint
+
+import "context"
+
+// levelKey is the context key for the level set with WithLevel.
+type levelKey struct{}
+
+// WithLevel returns a copy of ctx carrying a color level, for example the level a client requested for one response.
+// Rendering helpers read it with LevelFromContext, so colored and plain output can be produced in the same process
+// without changing the global state that ForceColor and ForceLevel control.
+func WithLevel(ctx context.Context, level Level) context.Context {
+	return context.WithValue(ctx, levelKey{}, level)
+}
+
+// LevelFromContext returns the level set with WithLevel on ctx or one of its parents,
+// and falls back to ColorLevel when none is set.
+func LevelFromContext(ctx context.Context) Level {
+	if level, ok := ContextLevel(ctx); ok {
+		return level
+	}
+	return ColorLevel()
+}
+
+// ContextLevel returns the level set with WithLevel on ctx or one of its parents, and whether one was set.
+// Writers that detect the level of their own destination, such as the slogcolor handler, use it to let a context
+// override their level only when one was requested.
+func ContextLevel(ctx context.Context) (Level, bool) {
+	level, ok := ctx.Value(levelKey{}).(Level)
+	return level, ok
+}
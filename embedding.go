@@ -0,0 +1,25 @@
+package glint
+
+import "github.com/droqsic/glint/internal/core"
+
+type Embedding string // Embedding identifies an editor hosting the terminal.
+
+const (
+	EmbeddingNone       Embedding = ""                       // EmbeddingNone indicates that no editor hosts the terminal
+	EmbeddingEmacsShell Embedding = core.EmbeddingEmacsShell // EmbeddingEmacsShell is M-x shell or another comint buffer: Level16 despite TERM=dumb
+	EmbeddingEmacsTerm  Embedding = core.EmbeddingEmacsTerm  // EmbeddingEmacsTerm is M-x term or ansi-term: Level16 through TERM=eterm-color
+	EmbeddingEmacsVterm Embedding = core.EmbeddingEmacsVterm // EmbeddingEmacsVterm is the vterm package: the level TERM describes
+	EmbeddingEmacsEat   Embedding = core.EmbeddingEmacsEat   // EmbeddingEmacsEat is the eat package: the level TERM=eat-truecolor or a lesser variant describes
+	EmbeddingEmacsTramp Embedding = core.EmbeddingEmacsTramp // EmbeddingEmacsTramp is a remote command run by TRAMP: LevelNone
+	EmbeddingEmacs      Embedding = core.EmbeddingEmacs      // EmbeddingEmacs is any other Emacs buffer, such as M-x compile: the level TERM describes
+	EmbeddingVim        Embedding = core.EmbeddingVim        // EmbeddingVim is a Vim :terminal window: the level TERM describes
+	EmbeddingNeovim     Embedding = core.EmbeddingNeovim     // EmbeddingNeovim is a Neovim :terminal buffer: the level TERM describes
+)
+
+// DetectEmbedding reports which editor hosts the terminal, from INSIDE_EMACS, NVIM and VIM_TERMINAL.
+// Editors often pass on variables that describe the terminal Emacs or Vim itself runs in, or set TERM=dumb for buffers
+// that still render colors, so detection applies the level listed for each embedding before the other heuristics.
+// Variables such as NO_COLOR, FORCE_COLOR and the config file still take precedence.
+func DetectEmbedding() Embedding {
+	return Embedding(core.DetectEmbedding(core.GetEnvCache))
+}
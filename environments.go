@@ -0,0 +1,108 @@
+package glint
+
+import "github.com/droqsic/glint/internal/core"
+
+// Environment describes a real-world terminal environment by the variables it sets, what it supports,
+// and what glint detects in it.
+type Environment struct {
+	Name         string            // Name identifies the terminal, such as "iTerm2" or "tmux in kitty"
+	OS           string            // OS is the GOOS the environment typically runs on
+	Env          map[string]string // Env holds the variables the terminal sets that matter for detection
+	Level        Level             // Level is the color level the terminal supports
+	Detected     Level             // Detected is the level TerminalColorLevel reports from Env, which differs from Level only for gaps
+	Gap          string            // Gap explains why Detected differs from Level, empty if detection is right
+	Capabilities Capabilities      // Capabilities are the features the terminal supports besides colors
+	Multiplexer  string            // Multiplexer is the multiplexer glint reports, such as "tmux", empty if there is none
+	CI           string            // CI is the CI provider glint reports, such as "github-actions", empty outside CI
+	Embedding    Embedding         // Embedding is the editor glint reports as hosting the terminal, EmbeddingNone if there is none
+}
+
+// Capabilities are the features of a terminal, beyond its color level, that glint's output relies on.
+type Capabilities struct {
+	Hyperlinks      bool // Hyperlinks reports that OSC 8 hyperlinks are clickable
+	BackgroundQuery bool // BackgroundQuery reports that the terminal answers the OSC 11 query DetectBackground sends
+}
+
+// modern are the capabilities of current graphical terminal emulators.
+var modern = Capabilities{Hyperlinks: true, BackgroundQuery: true}
+
+// gap marks e as a known gap, where the variables don't reveal the level the terminal supports and glint detects another.
+func gap(e Environment, detected Level, reason string) Environment {
+	e.Detected, e.Gap = detected, reason
+	return e
+}
+
+// tmuxEnv returns the variables of a tmux session started from a terminal with the given environment.
+// tmux replaces TERM and TERM_PROGRAM and passes the other variables through.
+func tmuxEnv(outer map[string]string) map[string]string {
+	env := map[string]string{"TERM": "tmux-256color", "TERM_PROGRAM": "tmux", "TMUX": "/tmp/tmux-1000/default,1234,0"}
+	for key, value := range outer {
+		if _, ok := env[key]; !ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// KnownEnvironments returns the real-world environments glint is tested against, with the variables each one sets,
+// the level and capabilities it supports, and the results detection gives. Tools can use the table to test their own
+// output across terminals. Each call returns a new copy.
+func KnownEnvironments() []Environment {
+	tmuxGap := "tmux passes COLORTERM on but reduces 24-bit colors to 256 unless the RGB terminal feature is configured"
+
+	iterm := map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor", "TERM_PROGRAM": "iTerm.app", "TERM_PROGRAM_VERSION": "3.5.0"}
+	terminalApp := map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "Apple_Terminal", "TERM_PROGRAM_VERSION": "453"}
+	vte := map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor", "VTE_VERSION": "7600"}
+	konsole := map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor", "KONSOLE_VERSION": "230805"}
+	kitty := map[string]string{"TERM": "xterm-kitty", "COLORTERM": "truecolor", "KITTY_WINDOW_ID": "1"}
+	alacritty := map[string]string{"TERM": "alacritty", "COLORTERM": "truecolor", "ALACRITTY_WINDOW_ID": "1"}
+
+	environments := []Environment{
+		{Name: "Windows Terminal", OS: "windows", Env: map[string]string{"WT_SESSION": "b9ef2a9c-0000-4000-8000-000000000000", "WT_PROFILE_ID": "{61c54bbd-c2c6-5271-96e7-009a87ff44bf}"}, Level: core.LevelTrue, Capabilities: modern},
+		gap(Environment{Name: "Windows console", OS: "windows", Env: map[string]string{}, Level: core.LevelTrue}, core.Level16, "the console sets no variables, although Windows 10 and later render 24-bit colors"),
+		{Name: "ConEmu", OS: "windows", Env: map[string]string{"ConEmuANSI": "ON"}, Level: core.Level256},
+		{Name: "ANSICON", OS: "windows", Env: map[string]string{"ANSICON": "120x1000 (120x30)"}, Level: core.Level256},
+		{Name: "WSL in Windows Terminal", OS: "linux", Env: map[string]string{"TERM": "xterm-256color", "WT_SESSION": "b9ef2a9c-0000-4000-8000-000000000000", "WSLENV": "WT_SESSION:WT_PROFILE_ID:"}, Level: core.LevelTrue, Capabilities: modern},
+		{Name: "iTerm2", OS: "darwin", Env: iterm, Level: core.LevelTrue, Capabilities: modern},
+		{Name: "Terminal.app", OS: "darwin", Env: terminalApp, Level: core.Level256, Capabilities: Capabilities{BackgroundQuery: true}},
+		{Name: "GNOME Terminal (VTE)", OS: "linux", Env: vte, Level: core.LevelTrue, Capabilities: modern},
+		{Name: "Konsole", OS: "linux", Env: konsole, Level: core.LevelTrue, Capabilities: modern},
+		{Name: "kitty", OS: "linux", Env: kitty, Level: core.LevelTrue, Capabilities: modern},
+		{Name: "Alacritty", OS: "linux", Env: alacritty, Level: core.LevelTrue, Capabilities: modern},
+		{Name: "WezTerm", OS: "linux", Env: map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor", "TERM_PROGRAM": "WezTerm"}, Level: core.LevelTrue, Capabilities: modern},
+		gap(Environment{Name: "xterm", OS: "linux", Env: map[string]string{"TERM": "xterm"}, Level: core.Level256, Capabilities: Capabilities{BackgroundQuery: true}}, core.Level16, "TERM=xterm is also set for terminals limited to 16 colors, although xterm itself has 256"),
+		{Name: "Linux console", OS: "linux", Env: map[string]string{"TERM": "linux"}, Level: core.Level16},
+		gap(Environment{Name: "tmux in iTerm2", OS: "darwin", Env: tmuxEnv(iterm), Level: core.Level256, Multiplexer: "tmux", Capabilities: Capabilities{BackgroundQuery: true}}, core.LevelTrue, tmuxGap),
+		{Name: "tmux in Terminal.app", OS: "darwin", Env: tmuxEnv(terminalApp), Level: core.Level256, Multiplexer: "tmux", Capabilities: Capabilities{BackgroundQuery: true}},
+		gap(Environment{Name: "tmux in GNOME Terminal", OS: "linux", Env: tmuxEnv(vte), Level: core.Level256, Multiplexer: "tmux", Capabilities: Capabilities{BackgroundQuery: true}}, core.LevelTrue, tmuxGap),
+		gap(Environment{Name: "tmux in Konsole", OS: "linux", Env: tmuxEnv(konsole), Level: core.Level256, Multiplexer: "tmux", Capabilities: Capabilities{BackgroundQuery: true}}, core.LevelTrue, tmuxGap),
+		gap(Environment{Name: "tmux in kitty", OS: "linux", Env: tmuxEnv(kitty), Level: core.Level256, Multiplexer: "tmux", Capabilities: Capabilities{BackgroundQuery: true}}, core.LevelTrue, tmuxGap),
+		gap(Environment{Name: "tmux in Alacritty", OS: "linux", Env: tmuxEnv(alacritty), Level: core.Level256, Multiplexer: "tmux", Capabilities: Capabilities{BackgroundQuery: true}}, core.LevelTrue, tmuxGap),
+		{Name: "GNU Screen", OS: "linux", Env: map[string]string{"TERM": "screen", "STY": "1234.pts-0.host"}, Level: core.Level16, Multiplexer: "screen"},
+		{Name: "Zellij", OS: "linux", Env: map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor", "ZELLIJ": "0"}, Level: core.LevelTrue, Multiplexer: "zellij"},
+		{Name: "VS Code", OS: "linux", Env: map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor", "TERM_PROGRAM": "vscode"}, Level: core.LevelTrue, Capabilities: modern},
+		{Name: "JetBrains IDEs", OS: "linux", Env: map[string]string{"TERM": "xterm-256color", "TERMINAL_EMULATOR": "JetBrains-JediTerm"}, Level: core.Level256},
+		{Name: "SSH session", OS: "linux", Env: map[string]string{"SSH_CONNECTION": "10.0.0.2 52314 10.0.0.1 22"}, Level: core.Level256},
+		{Name: "Termux", OS: "android", Env: map[string]string{"TERM": "xterm-256color", "TERMUX_VERSION": "0.118.0"}, Level: core.Level256},
+		{Name: "GitHub Actions", OS: "linux", Env: map[string]string{"CI": "true", "GITHUB_ACTIONS": "true"}, Level: core.Level16, CI: "github-actions"},
+		{Name: "GitLab CI", OS: "linux", Env: map[string]string{"CI": "true", "GITLAB_CI": "true", "TERM": "xterm"}, Level: core.Level16, CI: "gitlab"},
+		{Name: "Azure Pipelines", OS: "windows", Env: map[string]string{"TF_BUILD": "True"}, Level: core.Level16, CI: "azure-pipelines"},
+		{Name: "Emacs M-x shell", OS: "linux", Env: map[string]string{"TERM": "dumb", "INSIDE_EMACS": "29.1,comint", "COLORTERM": "truecolor"}, Level: core.Level16, Embedding: EmbeddingEmacsShell},
+		{Name: "Emacs M-x term", OS: "linux", Env: map[string]string{"TERM": "eterm-color", "INSIDE_EMACS": "29.1,term:0.96"}, Level: core.Level16, Embedding: EmbeddingEmacsTerm},
+		{Name: "Emacs vterm", OS: "linux", Env: map[string]string{"TERM": "xterm-256color", "INSIDE_EMACS": "vterm"}, Level: core.Level256, Embedding: EmbeddingEmacsVterm},
+		{Name: "Emacs eat", OS: "linux", Env: map[string]string{"TERM": "eat-truecolor", "INSIDE_EMACS": "29.1,eat"}, Level: core.LevelTrue, Embedding: EmbeddingEmacsEat},
+		{Name: "Emacs TRAMP", OS: "linux", Env: map[string]string{"TERM": "dumb", "INSIDE_EMACS": "29.1,tramp:2.6.2"}, Level: core.LevelNone, Embedding: EmbeddingEmacsTramp},
+		{Name: "Emacs M-x compile", OS: "linux", Env: map[string]string{"TERM": "dumb", "INSIDE_EMACS": "29.1,compile"}, Level: core.LevelNone, Embedding: EmbeddingEmacs},
+		{Name: "Vim :terminal", OS: "linux", Env: map[string]string{"TERM": "xterm-256color", "VIM_TERMINAL": "901"}, Level: core.Level256, Embedding: EmbeddingVim},
+		{Name: "Neovim :terminal", OS: "linux", Env: map[string]string{"TERM": "xterm-256color", "NVIM": "/run/user/1000/nvim.1234.0"}, Level: core.Level256, Embedding: EmbeddingNeovim},
+		{Name: "Dumb terminal", OS: "linux", Env: map[string]string{"TERM": "dumb"}, Level: core.LevelNone},
+		{Name: "Dumb terminal with COLORTERM", OS: "linux", Env: map[string]string{"TERM": "dumb", "COLORTERM": "truecolor"}, Level: core.LevelNone},
+	}
+
+	for i, e := range environments {
+		if e.Gap == "" {
+			environments[i].Detected = e.Level
+		}
+	}
+	return environments
+}
@@ -0,0 +1,48 @@
+package glint
+
+import (
+	"os"
+
+	"github.com/droqsic/glint/internal/core"
+)
+
+// Explanation describes the outcome of color detection for stdout and the rule that decided it.
+type Explanation struct {
+	Supported  bool   // Supported is the result of ColorSupport
+	Level      Level  // Level is the result of ColorLevel
+	Attributes bool   // Attributes is the result of AttributeSupport, which NoColorColorOnly keeps under NO_COLOR
+	Rule       string // Rule names the deciding setting, such as "ForceLevel", "NO_COLOR", "TERM=xterm-256color" or "config [mytool] mode=never"
+}
+
+// Explain reports the color support and level of stdout together with the rule that decided them.
+// Rules are checked in order of precedence: ForceColor and ForceLevel, an application scoped mode registered with
+// SetAppName, whether stdout is a terminal, the application scoped level, NO_COLOR, FORCE_COLOR, COLOR_16, COLOR_256 and COLOR_24, the config file,
+// and finally the terminal heuristics. Unlike ColorSupport and ColorLevel, the result is not cached.
+func Explain() Explanation {
+	if s := current.Load(); s.rule != "" {
+		return s.result().explanation(s.rule)
+	}
+
+	if _, forced, _ := core.AppColorLevel(); !forced && !isTerminal(os.Stdout) {
+		return Explanation{Rule: "stdout is not a terminal"}
+	}
+
+	level, rule := core.ExplainColorLevel()
+	return outcomeFor(level, rule).explanation(rule)
+}
+
+// DetectWithEnv evaluates color detection against an explicit environment in the key=value form of os.Environ,
+// such as the environment about to be passed to an exec.Cmd, instead of the process environment. The application
+// registered with SetAppName and the config file the given environment points to through XDG_CONFIG_HOME, HOME or
+// APPDATA are taken into account.
+// Forced settings and whether any output is a terminal are not, since they belong to this process.
+func DetectWithEnv(env []string) Explanation {
+	level, rule := core.ExplainColorLevelEnv(env)
+	return outcomeFor(level, rule).explanation(rule)
+}
+
+// ConfigPath returns the location of the config file glint reads, $XDG_CONFIG_HOME/glint/config or the
+// equivalent in the platform's user config directory. The file is optional.
+func ConfigPath() string {
+	return core.ConfigPath()
+}
@@ -0,0 +1,87 @@
+package glint
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/droqsic/glint/internal/ansi"
+)
+
+// Strip removes every escape sequence from s, leaving the text a terminal would print.
+func Strip(s string) string {
+	return ansi.Strip(s)
+}
+
+// Downsample rewrites the colors in s so a terminal with the given level can show them, replacing each color with its
+// closest equivalent. At LevelNone colors are removed while attributes such as bold are kept.
+// Other escape sequences are left untouched.
+func Downsample(s string, level Level) string {
+	var b strings.Builder
+	for len(s) > 0 {
+		tok, ok := ansi.Next(s)
+		if !ok {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(downsampleToken(tok, level))
+		s = s[len(tok.Raw):]
+	}
+	return b.String()
+}
+
+// ToPlain copies r to w without escape sequences, like Strip. Sequences split across reads are recognized,
+// and output is flushed whenever a read returns, so interactive programs can be piped through it.
+func ToPlain(r io.Reader, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	err := ansi.Scan(flushReader{r, bw}, func(tok ansi.Token) error {
+		if tok.Kind != ansi.TokenText {
+			return nil
+		}
+		_, err := bw.WriteString(tok.Raw)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ToLevel copies r to w with its colors downsampled to level, like Downsample. Output is flushed like ToPlain.
+func ToLevel(r io.Reader, w io.Writer, level Level) error {
+	bw := bufio.NewWriter(w)
+	err := ansi.Scan(flushReader{r, bw}, func(tok ansi.Token) error {
+		_, err := bw.WriteString(downsampleToken(tok, level))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// downsampleToken returns tok with its colors downsampled to level if it is an SGR sequence, and unchanged otherwise.
+func downsampleToken(tok ansi.Token, level Level) string {
+	if !tok.IsSGR() {
+		return tok.Raw
+	}
+	params, ok := ansi.DownsampleSGR(tok.Params(), level)
+	if !ok {
+		return ""
+	}
+	return "\x1b[" + params + "m"
+}
+
+// flushReader flushes a buffered writer before every read, so output never waits for input that may not come.
+type flushReader struct {
+	r io.Reader
+	w *bufio.Writer
+}
+
+// Read flushes the writer and reads from the underlying reader.
+func (f flushReader) Read(p []byte) (int, error) {
+	if err := f.w.Flush(); err != nil {
+		return 0, err
+	}
+	return f.r.Read(p)
+}
@@ -1,104 +1,225 @@
 package glint
 
 import (
+	"io"
 	"os"
 	"runtime"
 	"sync"
+	"sync/atomic"
 
 	"github.com/droqsic/glint/internal/core"
 	"github.com/droqsic/glint/internal/platform"
 	"github.com/droqsic/probe"
 )
 
+// outcome is the result of color detection for one destination.
+type outcome struct {
+	supported bool       // supported reports whether colors may be used
+	level     core.Level // level is the color level, LevelNone if supported is false
+	noColor   bool       // noColor reports that NO_COLOR is the only reason colors are disabled
+}
+
+// snapshot is an immutable view of the color configuration. Every change stores a new snapshot,
+// so readers never observe a support flag and a level from different configurations.
+type snapshot struct {
+	rule    string           // rule is "ForceColor" or "ForceLevel" for forced settings, empty for automatic detection
+	result  func() outcome   // result returns the detection outcome for stdout, computing it at most once
+	refresh func() *snapshot // refresh returns an equivalent snapshot that computes its results again
+}
+
 var (
-	colorSupport           bool       // colorSupport stores the detected terminal color support status
-	colorSupportOnce       sync.Once  // colorSupportOnce ensures color support detection runs only once
-	colorLevel             core.Level // colorLevel stores the detected terminal color level
-	colorLevelOnce         sync.Once  // colorLevelOnce ensures color level detection runs only once
-	forceColorSupport      *bool      // forceColorSupport allows overriding automatic color support detection, nil means automatic detection, non-nil means forced value
-	forceColorSupportMutex sync.Mutex // forceColorSupportMutex protects concurrent access to forceColorSupport
+	current    atomic.Pointer[snapshot] // current holds the active snapshot and is read without locking
+	stateMutex sync.Mutex               // stateMutex serializes writers replacing the snapshot
 )
 
-// ColorSupport determines whether the current terminal supports color output.
-// It checks if the output is a terminal and if the terminal supports color.
-// The result is cached after the first call for performance. This function is thread-safe.
-func ColorSupport() bool {
-	forceColorSupportMutex.Lock()
+// init installs the initial snapshot, which detects color support automatically.
+func init() {
+	current.Store(autoSnapshot())
+}
 
-	if forceColorSupport != nil {
-		defer forceColorSupportMutex.Unlock()
-		return *forceColorSupport
+// autoSnapshot returns a snapshot that detects color support on first use.
+func autoSnapshot() *snapshot {
+	return newSnapshot("", func() outcome {
+		return detect(os.Stdout)
+	})
+}
+
+// newSnapshot returns a snapshot computing its results with result on first use.
+func newSnapshot(rule string, result func() outcome) *snapshot {
+	return &snapshot{rule: rule, result: sync.OnceValue(result), refresh: func() *snapshot {
+		return newSnapshot(rule, result)
+	}}
+}
+
+// update replaces the active snapshot with the one next returns, then notifies the OnChange listeners
+// if the effective level changed. Writers are serialized, and listeners run after the lock is released
+// in the order of the transitions. When there are listeners, the level of old is computed before next runs,
+// since next may clear the environment cache old detects from.
+func update(next func(old *snapshot) *snapshot) {
+	stateMutex.Lock()
+	old := current.Load()
+	if hasListeners() {
+		old.result()
 	}
+	s := next(old)
+	current.Store(s)
+	enqueue(old, s)
+	stateMutex.Unlock()
 
-	forceColorSupportMutex.Unlock()
+	dispatch()
+}
 
-	colorSupportOnce.Do(func() {
-		if !probe.IsTerminal(os.Stdout.Fd()) && !probe.IsCygwinTerminal(os.Stdout.Fd()) {
-			colorSupport = false
-			return
-		}
+// detect determines the color support and level for output written to w.
+// An application scoped mode of never, always or a level decides on its own, otherwise w must be a terminal.
+func detect(w io.Writer) outcome {
+	if level, forced, _ := core.AppColorLevel(); forced {
+		return outcome{supported: level != core.LevelNone, level: atLeast16(level)}
+	}
 
-		if core.TerminalColorLevel() == core.LevelNone {
-			colorSupport = false
-			return
-		}
+	if !isTerminal(w) {
+		return outcome{}
+	}
 
-		colorSupport = true
-	})
-	return colorSupport
+	return outcomeFor(core.ExplainColorLevel())
+}
+
+// isTerminal reports whether w is backed by a terminal. A fake terminal installed by glinttest decides for every writer.
+func isTerminal(w io.Writer) bool {
+	if f := core.ActiveFake(); f != nil {
+		return f.TTY
+	}
+
+	f, ok := w.(interface{ Fd() uintptr })
+	return ok && (probe.IsTerminal(f.Fd()) || probe.IsCygwinTerminal(f.Fd()))
+}
+
+// atLeast16 raises any level but LevelNone to at least Level16.
+func atLeast16(level core.Level) core.Level {
+	if level == core.LevelNone {
+		return level
+	}
+	return max(level, core.Level16)
+}
+
+// ColorSupport determines whether the current terminal supports color output.
+// It checks if the output is a terminal and if the terminal supports color.
+// An application scoped mode registered with SetAppName, such as MYTOOL_COLOR=always, decides even if the output is not
+// a terminal. The result is cached after the first call for performance. This function is thread-safe and lock-free.
+func ColorSupport() bool {
+	return current.Load().result().supported
 }
 
 // ColorLevel determines the color support level of the current terminal.
-// It checks if the output is a terminal and what level of color it supports.
-// The result is cached after the first call for performance. This function is thread-safe.
+// It returns LevelNone if ColorSupport reports no color support, and at least Level16 otherwise,
+// so forcing color on a destination that isn't a terminal still yields basic colors.
+// The result is cached after the first call for performance. This function is thread-safe and lock-free.
 func ColorLevel() core.Level {
-	if ColorSupport() {
-		colorLevelOnce.Do(func() {
-			if !probe.IsTerminal(os.Stdout.Fd()) && !probe.IsCygwinTerminal(os.Stdout.Fd()) {
-				colorLevel = core.LevelNone
-				return
-			}
-			colorLevel = core.TerminalColorLevel()
-		})
+	return current.Load().result().level
+}
+
+// ColorSupportFor determines whether output written to w can be rendered in color.
+// It applies the same rules as ColorSupport to w instead of stdout. Writers that are not backed by a file descriptor,
+// such as buffers and network connections, are reported as not supporting color unless ForceColor(true) was called.
+// Unlike ColorSupport, the result is not cached since the same process may write to many destinations. This function is thread-safe.
+func ColorSupportFor(w io.Writer) bool {
+	return resultFor(w).supported
+}
+
+// ColorLevelFor determines the color support level for output written to w, see ColorSupportFor.
+// A level set with ForceLevel applies to every writer.
+func ColorLevelFor(w io.Writer) core.Level {
+	return resultFor(w).level
+}
+
+// resultFor returns the forced results if any, and detects color support for w otherwise.
+func resultFor(w io.Writer) outcome {
+	if s := current.Load(); s.rule != "" {
+		return s.result()
 	}
-	return colorLevel
+	return detect(w)
 }
 
 // ForceColor overrides automatic color support detection with a fixed value.
 // This is useful for applications that want to explicitly enable or disable color regardless of terminal capabilities.
 // However, it still respects the NO_COLOR environment variable - if NO_COLOR is set, colors will be disabled regardless.
 func ForceColor(value bool) {
-	forceColorSupportMutex.Lock()
-	defer forceColorSupportMutex.Unlock()
+	update(func(*snapshot) *snapshot {
+		noColor := value && core.GetEnvCache(core.EnvNoColor) != ""
+		if noColor {
+			value = false
+		}
 
-	if value && core.GetEnvCache(core.EnvNoColor) != "" {
-		value = false
-	}
+		vtEnabled := true
+		if value && runtime.GOOS == "windows" {
+			vtEnabled = platform.EnableVirtualTerminal()
+		}
+
+		return newSnapshot("ForceColor", func() outcome {
+			switch {
+			case !value:
+				return outcome{noColor: noColor}
+			case !vtEnabled:
+				return outcome{supported: true, level: core.Level16}
+			default:
+				return outcome{supported: true, level: max(core.TerminalColorLevel(), core.Level16)}
+			}
+		})
+	})
+}
 
-	if value && runtime.GOOS == "windows" {
-		vtEnabled := platform.EnableVirtualTerminal()
-		if !vtEnabled {
-			colorLevelOnce = sync.Once{}
-			colorLevelOnce.Do(func() {
-				colorLevel = core.Level16
-			})
+// ForceLevel overrides automatic detection with a fixed color level, enabling color support for any level but LevelNone.
+// Like ForceColor, it respects the NO_COLOR environment variable, and on Windows consoles without virtual terminal
+// processing the level is limited to Level16.
+func ForceLevel(level core.Level) {
+	update(func(*snapshot) *snapshot {
+		noColor := level != core.LevelNone && core.GetEnvCache(core.EnvNoColor) != ""
+		if noColor {
+			level = core.LevelNone
 		}
-	}
 
-	forceColorSupport = &value
-	colorSupportOnce = sync.Once{}
+		if level > core.Level16 && runtime.GOOS == "windows" && !platform.EnableVirtualTerminal() {
+			level = core.Level16
+		}
+
+		return newSnapshot("ForceLevel", func() outcome {
+			return outcome{supported: level != core.LevelNone, level: level, noColor: noColor}
+		})
+	})
+}
+
+// SetAppName registers the application whose scoped environment variables are consulted before the generic ones.
+// The name is upper-cased with other characters than letters and digits replaced by underscores, so SetAppName("mytool")
+// makes MYTOOL_COLOR=never disable color and MYTOOL_COLOR_LEVEL=256 select the 256 color palette.
+// MYTOOL_COLOR accepts auto, always, never or a level, and MYTOOL_COLOR_LEVEL accepts none, 16, 256 or truecolor.
+// Both take precedence over NO_COLOR, which configures color for every program while these configure it for one.
+// A mode of never, always or a level also applies if the output is not a terminal, while the level variable only
+// chooses the level of a terminal, like COLOR_256 does. Forced settings from ForceColor and ForceLevel still win.
+// Cached detection results are discarded so the variables apply to the next call.
+func SetAppName(name string) {
+	update(func(old *snapshot) *snapshot {
+		core.SetAppName(name)
+		return old.refresh()
+	})
+}
+
+// Refresh re-reads the environment variables and the config file and discards cached detection results,
+// so changes made with os.Setenv since the first detection take effect. Forced settings from ForceColor and
+// ForceLevel are kept, see ResetColor to clear them. This function is thread-safe.
+func Refresh() {
+	update(func(old *snapshot) *snapshot {
+		core.ClearCache()
+		return old.refresh()
+	})
+
+	backgroundMutex.Lock()
+	backgroundOnce = sync.Once{}
+	backgroundMutex.Unlock()
 }
 
 // ResetColor resets color support detection to automatic mode, clearing any previously forced settings.
 // This allows the system to detect terminal capabilities again, and also clears any previously forced settings.
 func ResetColor() {
-	forceColorSupportMutex.Lock()
-	defer forceColorSupportMutex.Unlock()
-
-	forceColorSupport = nil
-	colorSupportOnce = sync.Once{}
-	colorLevelOnce = sync.Once{}
-
-	colorSupport = false
-	colorLevel = core.LevelNone
+	update(func(*snapshot) *snapshot {
+		return autoSnapshot()
+	})
 }
@@ -0,0 +1,73 @@
+// Package glinttest provides a fake terminal for testing code that uses glint.
+//
+// Terminal replaces the process environment and the terminal attached to stdout for the duration of a test,
+// so colored output can be tested deterministically whether the tests run in a terminal, in CI or piped to a file:
+//
+//	func TestBanner(t *testing.T) {
+//		glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, TTY: true})
+//		var buf bytes.Buffer
+//		printBanner(&buf)
+//		glinttest.AssertOutput(t, buf.String(), "<bold red>error<reset>: disk full\n")
+//	}
+//
+// Annotate and AssertGolden snapshot output in a readable form that keeps the styles, compared against golden files
+// that go test -glinttest.update rewrites.
+//
+// The fake terminal is process wide, so tests using it must not run in parallel.
+package glinttest
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// Size is the size of the fake terminal in cells.
+type Size struct {
+	Columns int // Columns is the width of the terminal
+	Rows    int // Rows is the height of the terminal
+}
+
+// Options describes the fake terminal installed by Terminal.
+type Options struct {
+	Level      glint.Level       // Level is the color level the terminal supports, used in place of the TERM and COLORTERM heuristics
+	TTY        bool              // TTY reports stdout and every other writer as a terminal
+	Env        map[string]string // Env replaces the process environment, so NO_COLOR, FORCE_COLOR and application variables in it still apply
+	Size       Size              // Size is reported by glint.TerminalSize, 80x24 if zero
+	Background glint.Background  // Background is reported by glint.DetectBackground, BackgroundAuto uses COLORFGBG from Env and then dark
+}
+
+// Terminal installs a fake terminal described by opts until the test and its subtests complete.
+// Forced settings and the background override are cleared when the fake terminal is installed and again when it is
+// removed, and a terminal installed earlier in the same test is restored afterwards. The config file is not read.
+func Terminal(t testing.TB, opts Options) {
+	t.Helper()
+
+	size := opts.Size
+	if size == (Size{}) {
+		size = Size{Columns: 80, Rows: 24}
+	}
+
+	previous := core.SetFake(&core.Fake{
+		Env:        maps.Clone(opts.Env),
+		Level:      opts.Level,
+		TTY:        opts.TTY,
+		Columns:    size.Columns,
+		Rows:       size.Rows,
+		Background: int8(opts.Background),
+	})
+	reset()
+
+	t.Cleanup(func() {
+		core.SetFake(previous)
+		reset()
+	})
+}
+
+// reset discards detection results and overrides made under the previous terminal.
+func reset() {
+	glint.SetBackground(glint.BackgroundAuto)
+	glint.ResetColor()
+}
@@ -0,0 +1,182 @@
+package glinttest
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/ansi"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// update makes AssertGolden write the golden files instead of comparing against them, run go test -glinttest.update.
+var update = flag.Bool("glinttest.update", false, "update the golden files of glinttest.AssertGolden")
+
+// attrLabels names the text attributes in the bit order of ansi.Attr.
+var attrLabels = [...]string{"bold", "dim", "italic", "underline", "blink", "reverse", "hidden", "strike", "overline"}
+
+// Annotate renders s in a readable form that describes the style of each run of text instead of the escape sequences
+// producing it, so "\x1b[1;38;2;255;0;0mfail\x1b[0m" reads "[fg=#ff0000 bold]fail[/]". Colors are first converted to what
+// a terminal with the given level displays, which makes the result independent of the terminal running the tests.
+// Basic colors are named, such as fg=bright-red, 256 colors are shown by index and true colors in hex. Attributes follow
+// the colors, and an open hyperlink is shown as link=URI. Styles are closed at the end of each line and reopened on the
+// next, so golden files diff line by line. Other escape sequences are shown Go-quoted, such as \x1b[2J.
+func Annotate(s string, level glint.Level) string {
+	var b strings.Builder
+	var state ansi.State
+	open := ""
+
+	for len(s) > 0 {
+		tok, ok := ansi.Next(s)
+		if !ok {
+			b.WriteString(quote(s))
+			break
+		}
+		s = s[len(tok.Raw):]
+
+		if tok.Kind != ansi.TokenText {
+			if _, _, link := tok.Hyperlink(); link || tok.IsSGR() {
+				state.Apply(tok)
+			} else {
+				b.WriteString(quote(tok.Raw))
+			}
+			continue
+		}
+
+		label := styleLabel(state, level)
+		for i, line := range strings.Split(tok.Raw, "\n") {
+			if i > 0 {
+				if open != "" {
+					b.WriteString("[/]")
+					open = ""
+				}
+				b.WriteByte('\n')
+			}
+			if line == "" {
+				continue
+			}
+			if label != open {
+				if open != "" {
+					b.WriteString("[/]")
+				}
+				if label != "" {
+					b.WriteString("[" + label + "]")
+				}
+				open = label
+			}
+			b.WriteString(line)
+		}
+	}
+
+	if open != "" {
+		b.WriteString("[/]")
+	}
+	return b.String()
+}
+
+// styleLabel describes the colors, attributes and hyperlink of state as seen at level, empty for the default style.
+func styleLabel(state ansi.State, level glint.Level) string {
+	var parts []string
+	if fg := state.Fg.Convert(level); !fg.IsDefault() {
+		parts = append(parts, "fg="+colorLabel(fg))
+	}
+	if bg := state.Bg.Convert(level); !bg.IsDefault() {
+		parts = append(parts, "bg="+colorLabel(bg))
+	}
+	for i, name := range attrLabels {
+		if state.Attrs&(1<<i) != 0 {
+			parts = append(parts, name)
+		}
+	}
+	if state.Link != "" {
+		parts = append(parts, "link="+state.Link)
+	}
+	return strings.Join(parts, " ")
+}
+
+// colorLabel names a color: basic colors by name, 256 colors by index and true colors in hex.
+func colorLabel(c core.Color) string {
+	switch c.Kind {
+	case core.ColorANSI:
+		if c.Index >= 8 {
+			return "bright-" + colorNames[c.Index&7]
+		}
+		return colorNames[c.Index]
+	case core.ColorANSI256:
+		return strconv.Itoa(int(c.Index))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// AssertGolden compares Annotate(got, level) with the golden file testdata/<name>.golden and reports an error with a
+// side-by-side diff if they differ. Running go test with -glinttest.update writes the golden file instead.
+func AssertGolden(t testing.TB, name, got string, level glint.Level) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	annotated := Annotate(got, level)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("cannot create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(annotated), 0o644); err != nil {
+			t.Fatalf("cannot update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("golden file %s does not exist, run go test -glinttest.update to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("cannot read golden file: %v", err)
+	}
+
+	want = bytes.ReplaceAll(want, []byte("\r\n"), []byte("\n"))
+	if string(want) != annotated {
+		t.Errorf("output differs from %s, run go test -glinttest.update to accept it\n%s", path, sideBySide(string(want), annotated))
+	}
+}
+
+// sideBySide lays out want and got in two columns, marking the lines that differ with "!".
+func sideBySide(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+
+	width := len("want")
+	for _, line := range wantLines {
+		width = max(width, ansi.StringWidth(line))
+	}
+
+	var b strings.Builder
+	row := func(mark, left, right string) {
+		b.WriteString(mark + " " + left + strings.Repeat(" ", width-ansi.StringWidth(left)) + " | " + right)
+		b.WriteString("\n")
+	}
+
+	row(" ", "want", "got")
+	for i := range max(len(wantLines), len(gotLines)) {
+		var left, right string
+		if i < len(wantLines) {
+			left = wantLines[i]
+		}
+		if i < len(gotLines) {
+			right = gotLines[i]
+		}
+		mark := " "
+		if left != right || i >= len(wantLines) || i >= len(gotLines) {
+			mark = "!"
+		}
+		row(mark, left, right)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,159 @@
+package glinttest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/droqsic/glint/internal/ansi"
+)
+
+// colorNames are the names of the eight basic colors, in SGR order.
+var colorNames = [...]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// attrNames maps the SGR parameters of text attributes and resets to their names.
+var attrNames = map[int]string{
+	0: "reset", 1: "bold", 2: "dim", 3: "italic", 4: "underline", 5: "blink", 6: "blink", 7: "reverse", 8: "hidden",
+	9: "strike", 21: "underline", 22: "/bold", 23: "/italic", 24: "/underline", 25: "/blink", 27: "/reverse",
+	28: "/hidden", 29: "/strike", 39: "/fg", 49: "/bg", 53: "overline", 55: "/overline", 59: "/ul",
+}
+
+// Visible makes the escape sequences in s readable. Each SGR sequence becomes a tag listing its parameters by name,
+// so "\x1b[1;31m" reads "<bold red>" and "\x1b[0m" reads "<reset>". Bright colors are prefixed with "bright-" and
+// background colors with "bg-", 256 colors are shown by index as in "<bg-196>" and true colors as in "<#ff8700>".
+// Hyperlinks read "<link=URI>" and "</link>", and every other sequence is shown Go-quoted, such as \x1b[2J.
+func Visible(s string) string {
+	var b strings.Builder
+	for len(s) > 0 {
+		tok, ok := ansi.Next(s)
+		if !ok {
+			// An unterminated sequence at the end is shown as is.
+			b.WriteString(quote(s))
+			break
+		}
+		s = s[len(tok.Raw):]
+
+		switch params, uri, link := tok.Hyperlink(); {
+		case tok.Kind == ansi.TokenText:
+			b.WriteString(tok.Raw)
+		case tok.IsSGR():
+			b.WriteString("<" + strings.Join(sgrNames(tok.Params()), " ") + ">")
+		case link && uri == "":
+			b.WriteString("</link>")
+		case link:
+			if params != "" {
+				uri = params + " " + uri
+			}
+			b.WriteString("<link=" + uri + ">")
+		default:
+			b.WriteString(quote(tok.Raw))
+		}
+	}
+	return b.String()
+}
+
+// AssertOutput reports an error if got, made readable with Visible, differs from want.
+// Write want in the form Visible produces, such as "<bold red>error<reset>".
+func AssertOutput(t testing.TB, got, want string) {
+	t.Helper()
+
+	if visible := Visible(got); visible != want {
+		t.Errorf("unexpected output\n got: %s\nwant: %s", visible, want)
+	}
+}
+
+// sgrNames returns the names of the parameters of an SGR sequence.
+func sgrNames(params string) []string {
+	if params == "" {
+		return []string{"reset"}
+	}
+
+	fields := strings.Split(params, ";")
+	names := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if sub := strings.Split(field, ":"); len(sub) > 1 {
+			names = append(names, subParamName(sub))
+			continue
+		}
+
+		n, err := strconv.Atoi(field)
+		switch {
+		case err != nil:
+			names = append(names, "sgr-"+field)
+		case n >= 30 && n <= 37:
+			names = append(names, colorNames[n-30])
+		case n >= 40 && n <= 47:
+			names = append(names, "bg-"+colorNames[n-40])
+		case n >= 90 && n <= 97:
+			names = append(names, "bright-"+colorNames[n-90])
+		case n >= 100 && n <= 107:
+			names = append(names, "bg-bright-"+colorNames[n-100])
+		case n == 38 || n == 48 || n == 58:
+			name, used := extendedName(fields[i+1:])
+			i += used
+			names = append(names, colorPrefix(n)+name)
+		case attrNames[n] != "":
+			names = append(names, attrNames[n])
+		default:
+			names = append(names, "sgr-"+field)
+		}
+	}
+	return names
+}
+
+// subParamName returns the name of a colon separated SGR field such as 38:5:196 or 4:3.
+func subParamName(sub []string) string {
+	switch sub[0] {
+	case "4":
+		if sub[1] == "0" {
+			return "/underline"
+		}
+		return "underline"
+	case "38", "48", "58":
+		rest := sub[1:]
+		// The ITU form carries a color space id before the channels of an RGB color.
+		if len(rest) == 5 && rest[0] == "2" {
+			rest = append(rest[:1:1], rest[2:]...)
+		}
+		n, _ := strconv.Atoi(sub[0])
+		name, _ := extendedName(rest)
+		return colorPrefix(n) + name
+	}
+	return "sgr-" + strings.Join(sub, ":")
+}
+
+// extendedName returns the name of the color following a 38, 48 or 58 parameter and how many arguments it used.
+func extendedName(args []string) (string, int) {
+	switch {
+	case len(args) >= 2 && args[0] == "5":
+		return args[1], 2
+	case len(args) >= 4 && args[0] == "2":
+		return fmt.Sprintf("#%02x%02x%02x", channel(args[1]), channel(args[2]), channel(args[3])), 4
+	}
+	return "?", len(args)
+}
+
+// colorPrefix returns the prefix naming the target of an extended color parameter.
+func colorPrefix(n int) string {
+	switch n {
+	case 48:
+		return "bg-"
+	case 58:
+		return "ul-"
+	}
+	return ""
+}
+
+// channel parses a color component, clamping it to the range of a byte.
+func channel(s string) int {
+	n, _ := strconv.Atoi(s)
+	return max(0, min(n, 255))
+}
+
+// quote shows s with control characters escaped, without the surrounding quotes.
+func quote(s string) string {
+	q := strconv.Quote(s)
+	return q[1 : len(q)-1]
+}
@@ -0,0 +1,248 @@
+package glint
+
+import (
+	"bufio"
+	"html"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/droqsic/glint/internal/ansi"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// HTMLOptions controls how ToHTML renders terminal output.
+// The zero value produces inline styles with the xterm palette.
+type HTMLOptions struct {
+	Classes     bool    // Classes emits CSS class names for basic colors and attributes instead of inline styles
+	ClassPrefix string  // ClassPrefix is prepended to every class name, "glint-" if empty or not made of [A-Za-z0-9_-]
+	Palette     Palette // Palette maps the basic colors to CSS colors, PaletteXterm if zero
+	Fragment    bool    // Fragment omits the surrounding <pre> element
+}
+
+// linkSchemes lists the URI schemes ToHTML turns into anchors. Links with other schemes are rendered as plain text.
+var linkSchemes = map[string]bool{"http": true, "https": true, "mailto": true, "ftp": true, "file": true}
+
+// ToHTML converts ANSI-colored text read from r into HTML written to w.
+// SGR colors at every level and the common text attributes are rendered as spans, OSC 8 hyperlinks become anchors,
+// and all text is escaped. Other escape sequences are dropped.
+// With Classes set, colors outside the basic 16 still use inline styles, see HTMLStylesheet for the class rules.
+func ToHTML(r io.Reader, w io.Writer, opts HTMLOptions) error {
+	h := htmlWriter{w: bufio.NewWriter(w), opts: opts}
+	h.opts.Palette = opts.Palette.orDefault()
+	h.opts.ClassPrefix = classPrefix(opts.ClassPrefix)
+
+	if !opts.Fragment {
+		h.w.WriteString(h.preTag())
+	}
+
+	err := ansi.Scan(r, func(tok ansi.Token) error {
+		if tok.Kind != ansi.TokenText {
+			h.state.Apply(tok)
+			return nil
+		}
+		h.text(tok.Raw)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	h.closeSpan()
+	h.closeLink()
+	if !opts.Fragment {
+		h.w.WriteString("</pre>")
+	}
+	return h.w.Flush()
+}
+
+// HTMLStylesheet returns the CSS rules for the class names ToHTML emits when opts.Classes is set.
+func HTMLStylesheet(opts HTMLOptions) string {
+	palette := opts.Palette.orDefault()
+	prefix := classPrefix(opts.ClassPrefix)
+
+	var b strings.Builder
+	b.WriteString("pre." + prefix + "term { color: " + palette.Foreground + "; background-color: " + palette.Background + "; }\n")
+	for i, color := range palette.Colors {
+		n := strconv.Itoa(i)
+		b.WriteString("." + prefix + "fg-" + n + " { color: " + color + "; }\n")
+		b.WriteString("." + prefix + "bg-" + n + " { background-color: " + color + "; }\n")
+	}
+	for _, rule := range htmlAttrs {
+		b.WriteString("." + prefix + rule.class + " { " + rule.style + " }\n")
+	}
+	return b.String()
+}
+
+// classPrefix returns prefix if it is safe to use in class attributes and selectors, "glint-" otherwise.
+func classPrefix(prefix string) string {
+	if prefix == "" {
+		return "glint-"
+	}
+	for _, r := range prefix {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return "glint-"
+		}
+	}
+	return prefix
+}
+
+// htmlAttrs maps text attributes to their class names and CSS declarations.
+// Decorations are combined into a single text-decoration-line declaration when inline styles are used.
+var htmlAttrs = [...]struct {
+	attr       ansi.Attr
+	class      string
+	style      string
+	decoration string
+}{
+	{ansi.AttrBold, "bold", "font-weight: bold;", ""},
+	{ansi.AttrDim, "dim", "opacity: 0.6;", ""},
+	{ansi.AttrItalic, "italic", "font-style: italic;", ""},
+	{ansi.AttrUnderline, "underline", "text-decoration-line: underline;", "underline"},
+	{ansi.AttrStrike, "strike", "text-decoration-line: line-through;", "line-through"},
+	{ansi.AttrOverline, "overline", "text-decoration-line: overline;", "overline"},
+	{ansi.AttrHidden, "hidden", "visibility: hidden;", ""},
+}
+
+// htmlWriter holds the state of a single ToHTML call.
+type htmlWriter struct {
+	w        *bufio.Writer
+	opts     HTMLOptions
+	state    ansi.State // state is the rendition requested by the input
+	span     ansi.State // span is the rendition of the open span, valid if spanOpen
+	spanOpen bool
+	link     string // link is the hyperlink URI the output reflects, empty if none
+	anchor   bool   // anchor is set while an <a> element for link is open
+}
+
+// preTag returns the opening <pre> element.
+func (h *htmlWriter) preTag() string {
+	if h.opts.Classes {
+		return `<pre class="` + h.opts.ClassPrefix + `term">`
+	}
+	return `<pre style="color: ` + html.EscapeString(h.opts.Palette.Foreground) + `; background-color: ` + html.EscapeString(h.opts.Palette.Background) + `;">`
+}
+
+// text writes escaped text, first bringing the open anchor and span in line with the current state.
+func (h *htmlWriter) text(s string) {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\n' && r != '\t' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+	if s == "" {
+		return
+	}
+
+	if h.state.Link != h.link {
+		h.closeSpan()
+		h.closeLink()
+		h.openLink(h.state.Link)
+	}
+
+	style := h.state
+	style.Link, style.LinkParams = "", ""
+	if !h.spanOpen || style != h.span {
+		h.closeSpan()
+		h.openSpan(style)
+	}
+
+	h.w.WriteString(html.EscapeString(s))
+}
+
+// openLink opens an anchor for uri if its scheme is safe to link to.
+func (h *htmlWriter) openLink(uri string) {
+	h.link = uri
+	if uri == "" {
+		return
+	}
+	if !safeLink(uri) {
+		return
+	}
+	h.w.WriteString(`<a href="` + html.EscapeString(uri) + `">`)
+	h.anchor = true
+}
+
+// safeLink reports whether uri is well formed and uses one of linkSchemes.
+func safeLink(uri string) bool {
+	u, err := url.Parse(uri)
+	return uri != "" && err == nil && linkSchemes[strings.ToLower(u.Scheme)]
+}
+
+// closeLink closes the open anchor, if any.
+func (h *htmlWriter) closeLink() {
+	if h.anchor {
+		h.w.WriteString("</a>")
+	}
+	h.link, h.anchor = "", false
+}
+
+// openSpan opens a span rendering style. Nothing is written for the default style.
+func (h *htmlWriter) openSpan(style ansi.State) {
+	if !style.Styled() {
+		return
+	}
+
+	var classes, styles, decorations []string
+
+	fg, bg := style.Fg, style.Bg
+	var fgDefault, bgDefault string
+	if style.Attrs&ansi.AttrReverse != 0 {
+		fg, bg = bg, fg
+		fgDefault, bgDefault = h.opts.Palette.Background, h.opts.Palette.Foreground
+	}
+	classes, styles = h.color(classes, styles, fg, fgDefault, "fg-", "color")
+	classes, styles = h.color(classes, styles, bg, bgDefault, "bg-", "background-color")
+
+	for _, rule := range htmlAttrs {
+		switch {
+		case style.Attrs&rule.attr == 0:
+		case h.opts.Classes:
+			classes = append(classes, h.opts.ClassPrefix+rule.class)
+		case rule.decoration != "":
+			decorations = append(decorations, rule.decoration)
+		default:
+			styles = append(styles, rule.style)
+		}
+	}
+	if len(decorations) > 0 {
+		styles = append(styles, "text-decoration-line: "+strings.Join(decorations, " ")+";")
+	}
+
+	h.w.WriteString("<span")
+	if len(classes) > 0 {
+		h.w.WriteString(` class="` + strings.Join(classes, " ") + `"`)
+	}
+	if len(styles) > 0 {
+		h.w.WriteString(` style="` + strings.Join(styles, " ") + `"`)
+	}
+	h.w.WriteString(">")
+	h.span, h.spanOpen = style, true
+}
+
+// color adds the class or inline style selecting c for property. A default color is rendered as fallback,
+// or not at all if fallback is empty.
+func (h *htmlWriter) color(classes, styles []string, c core.Color, fallback, kind, property string) ([]string, []string) {
+	if i, ok := c.BasicIndex(); ok && h.opts.Classes {
+		return append(classes, h.opts.ClassPrefix+kind+strconv.Itoa(int(i))), styles
+	}
+
+	css := h.opts.Palette.cssColor(c)
+	if css == "" {
+		css = fallback
+	}
+	if css == "" {
+		return classes, styles
+	}
+	return classes, append(styles, property+": "+html.EscapeString(css)+";")
+}
+
+// closeSpan closes the open span, if any.
+func (h *htmlWriter) closeSpan() {
+	if h.spanOpen && h.span.Styled() {
+		h.w.WriteString("</span>")
+	}
+	h.spanOpen = false
+}
@@ -0,0 +1,82 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/droqsic/glint/internal/core"
+)
+
+// DownsampleSGR rewrites the parameters of an SGR sequence so every color can be shown at level.
+// Colors are replaced by their closest equivalent, and dropped at LevelNone. Underline colors have no basic form
+// and are dropped below Level256. Attributes and unknown parameters are kept. It returns false for ok if nothing is
+// left of a non-empty parameter list, in which case the sequence should be dropped, since an empty list means reset.
+func DownsampleSGR(params string, level core.Level) (string, bool) {
+	if params == "" {
+		return params, true
+	}
+
+	fields := strings.Split(params, ";")
+	out := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		if strings.IndexByte(field, ':') >= 0 {
+			sub := strings.Split(field, ":")
+			switch n, _ := strconv.Atoi(sub[0]); n {
+			case 38, 48, 58:
+				rest := sub[1:]
+				// The ITU form carries a color space id before the channels of an RGB color.
+				if len(rest) == 5 && rest[0] == "2" {
+					rest = append(rest[:1:1], rest[2:]...)
+				}
+				color, _ := extendedColor(rest)
+				out = appendColor(out, n, color, level)
+			default:
+				out = append(out, field)
+			}
+			continue
+		}
+
+		n, err := strconv.Atoi(field)
+		switch {
+		case err != nil:
+			out = append(out, field)
+		case n == 38 || n == 48 || n == 58:
+			color, used := extendedColor(fields[i+1:])
+			i += used
+			out = appendColor(out, n, color, level)
+		case n >= 30 && n <= 37, n >= 90 && n <= 97, n >= 40 && n <= 47, n >= 100 && n <= 107:
+			if level != core.LevelNone {
+				out = append(out, field)
+			}
+		default:
+			out = append(out, field)
+		}
+	}
+
+	if len(out) == 0 {
+		return "", false
+	}
+	return strings.Join(out, ";"), true
+}
+
+// appendColor appends the parameters selecting color, converted to level, for the extended color parameter
+// param: 38 for the foreground, 48 for the background or 58 for the underline.
+func appendColor(out []string, param int, color core.Color, level core.Level) []string {
+	c := color.Convert(level)
+	if c.IsDefault() {
+		return out
+	}
+
+	switch param {
+	case 48:
+		return append(out, c.Params(true))
+	case 58:
+		if c.Kind == core.ColorANSI {
+			return out
+		}
+		return append(out, "58"+strings.TrimPrefix(c.Params(false), "38"))
+	}
+	return append(out, c.Params(false))
+}
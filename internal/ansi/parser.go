@@ -0,0 +1,179 @@
+package ansi
+
+import "strings"
+
+const (
+	ESC = '\x1b'   // ESC introduces every escape sequence
+	BEL = '\x07'   // BEL terminates OSC sequences in the xterm dialect
+	ST  = "\x1b\\" // ST is the string terminator used by OSC, DCS, SOS, PM and APC
+
+	Reset = "\x1b[0m" // Reset restores all SGR attributes to their defaults
+)
+
+type TokenKind uint8 // TokenKind identifies the type of a token produced by Next.
+
+const (
+	TokenText   TokenKind = iota // TokenText is a run of printable text and control characters
+	TokenCSI                     // TokenCSI is a control sequence such as SGR or cursor movement
+	TokenOSC                     // TokenOSC is an operating system command such as a hyperlink or title
+	TokenString                  // TokenString is a DCS, SOS, PM or APC control string
+	TokenEscape                  // TokenEscape is any other escape sequence, including malformed ones
+)
+
+// Token is a single unit of terminal output: either a run of text or one complete escape sequence.
+type Token struct {
+	Kind TokenKind // Kind is the type of the token
+	Raw  string    // Raw is the exact input that produced the token
+}
+
+// Next splits the first token off s and returns it.
+// It returns false if s is empty or starts with an escape sequence that is not yet terminated,
+// which lets streaming callers wait for more input before deciding what to do with it.
+func Next(s string) (Token, bool) {
+	if s == "" {
+		return Token{}, false
+	}
+
+	if s[0] != ESC {
+		if i := strings.IndexByte(s, ESC); i >= 0 {
+			return Token{Kind: TokenText, Raw: s[:i]}, true
+		}
+		return Token{Kind: TokenText, Raw: s}, true
+	}
+
+	if len(s) < 2 {
+		return Token{}, false
+	}
+
+	switch s[1] {
+	case '[':
+		return nextCSI(s)
+	case ']':
+		return nextString(s, TokenOSC, true)
+	case 'P', 'X', '^', '_':
+		return nextString(s, TokenString, false)
+	}
+
+	// Other escape sequences are ESC, any number of intermediate bytes, and a final byte.
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= 0x30 && c <= 0x7e:
+			return Token{Kind: TokenEscape, Raw: s[:i+1]}, true
+		case c < 0x20 || c > 0x7e:
+			return Token{Kind: TokenEscape, Raw: s[:i]}, true
+		}
+	}
+	return Token{}, false
+}
+
+// nextCSI scans a control sequence introduced by ESC [.
+func nextCSI(s string) (Token, bool) {
+	for i := 2; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= 0x40 && c <= 0x7e:
+			return Token{Kind: TokenCSI, Raw: s[:i+1]}, true
+		case c < 0x20 || c > 0x3f:
+			// A byte outside the CSI grammar aborts the sequence, drop what was read so far.
+			return Token{Kind: TokenEscape, Raw: s[:i]}, true
+		}
+	}
+	return Token{}, false
+}
+
+// nextString scans a control string terminated by ST, or by BEL when bel is true.
+func nextString(s string, kind TokenKind, bel bool) (Token, bool) {
+	for i := 2; i < len(s); i++ {
+		switch s[i] {
+		case BEL:
+			if bel {
+				return Token{Kind: kind, Raw: s[:i+1]}, true
+			}
+		case ESC:
+			if i+1 >= len(s) {
+				return Token{}, false
+			}
+			if s[i+1] == '\\' {
+				return Token{Kind: kind, Raw: s[:i+2]}, true
+			}
+			// Any other escape cancels the string, the escape itself is parsed separately.
+			return Token{Kind: TokenEscape, Raw: s[:i]}, true
+		}
+	}
+	return Token{}, false
+}
+
+// Params returns the parameter bytes of a CSI token, excluding intermediate and final bytes.
+func (t Token) Params() string {
+	if t.Kind != TokenCSI {
+		return ""
+	}
+	end := len(t.Raw) - 1
+	for end > 2 && t.Raw[end-1] >= 0x20 && t.Raw[end-1] <= 0x2f {
+		end--
+	}
+	return t.Raw[2:end]
+}
+
+// Final returns the final byte of a CSI token, or zero for any other token.
+func (t Token) Final() byte {
+	if t.Kind != TokenCSI {
+		return 0
+	}
+	return t.Raw[len(t.Raw)-1]
+}
+
+// IsSGR reports whether the token is a Select Graphic Rendition sequence.
+func (t Token) IsSGR() bool {
+	if t.Final() != 'm' {
+		return false
+	}
+	p := t.Params()
+	return p == "" || (p[0] >= '0' && p[0] <= ';')
+}
+
+// Data returns the payload of an OSC or control string token, without introducer and terminator.
+func (t Token) Data() string {
+	if t.Kind != TokenOSC && t.Kind != TokenString {
+		return ""
+	}
+	data := t.Raw[2:]
+	if strings.HasSuffix(data, ST) {
+		return data[:len(data)-len(ST)]
+	}
+	return strings.TrimSuffix(data, string(BEL))
+}
+
+// Hyperlink parses an OSC 8 hyperlink token and returns its parameters and URI.
+// An empty URI closes the currently open hyperlink.
+func (t Token) Hyperlink() (params, uri string, ok bool) {
+	if t.Kind != TokenOSC {
+		return "", "", false
+	}
+	data, found := strings.CutPrefix(t.Data(), "8;")
+	if !found {
+		return "", "", false
+	}
+	params, uri, ok = strings.Cut(data, ";")
+	return params, uri, ok
+}
+
+// Strip removes every escape sequence from s, including unterminated ones at the end.
+func Strip(s string) string {
+	if strings.IndexByte(s, ESC) < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for len(s) > 0 {
+		tok, ok := Next(s)
+		if !ok {
+			break
+		}
+		if tok.Kind == TokenText {
+			b.WriteString(tok.Raw)
+		}
+		s = s[len(tok.Raw):]
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/droqsic/glint/internal/core"
+)
+
+// ParseColorReport parses a terminal's reply to an OSC 10, 11 or 4 color query, such as
+// "\x1b]11;rgb:ffff/ffff/ffff\x1b\\". Each channel may have one to four hex digits.
+func ParseColorReport(reply string) (core.Color, bool) {
+	tok, ok := Next(reply)
+	if !ok || tok.Kind != TokenOSC {
+		return core.Color{}, false
+	}
+
+	data := tok.Data()
+	i := strings.LastIndexByte(data, ';')
+	if i < 0 {
+		return core.Color{}, false
+	}
+	spec, found := strings.CutPrefix(data[i+1:], "rgb:")
+	if !found {
+		return core.Color{}, false
+	}
+
+	parts := strings.Split(spec, "/")
+	if len(parts) != 3 {
+		return core.Color{}, false
+	}
+
+	var rgb [3]uint8
+	for i, part := range parts {
+		if len(part) < 1 || len(part) > 4 {
+			return core.Color{}, false
+		}
+		v, err := strconv.ParseUint(part, 16, 16)
+		if err != nil {
+			return core.Color{}, false
+		}
+		// Scale the value to 8 bits whatever the number of digits.
+		maxValue := uint64(1)<<(4*len(part)) - 1
+		rgb[i] = uint8(v * 255 / maxValue)
+	}
+	return core.Color{Kind: core.ColorRGB, R: rgb[0], G: rgb[1], B: rgb[2]}, true
+}
+
+// IsDeviceAttributes reports whether tok is a primary device attributes report, ESC [ ? ... c.
+// Terminals answer the DA1 query in order, so it marks the end of the replies to earlier queries.
+func IsDeviceAttributes(tok Token) bool {
+	return tok.Final() == 'c' && strings.HasPrefix(tok.Params(), "?")
+}
@@ -0,0 +1,80 @@
+package ansi
+
+import (
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	readSize   = 32 * 1024 // readSize is the size of the chunks Scan reads from its input
+	maxPending = 64 * 1024 // maxPending bounds how much of an unterminated sequence Scan buffers
+)
+
+// Scan reads r until EOF and calls fn for every token in the stream.
+// Escape sequences split across reads are reassembled before fn sees them,
+// and an unterminated sequence at the end of the stream, or one longer than maxPending,
+// is reported as a TokenEscape.
+// Scan stops at the first error returned by r or fn.
+func Scan(r io.Reader, fn func(Token) error) error {
+	buf := make([]byte, readSize)
+	var pending string
+
+	for {
+		n, err := r.Read(buf)
+		s := pending + string(buf[:n])
+		pending = ""
+
+		for len(s) > 0 {
+			tok, ok := Next(s)
+			if !ok && len(s) <= maxPending {
+				pending = s
+				break
+			}
+			if !ok {
+				tok = Token{Kind: TokenEscape, Raw: s}
+			}
+			// Hold back a rune split across reads so fn always sees whole characters.
+			if tok.Kind == TokenText && len(tok.Raw) == len(s) && err == nil {
+				if cut := PartialRune(s); cut > 0 {
+					pending = s[len(s)-cut:]
+					tok.Raw = s[:len(s)-cut]
+					if tok.Raw == "" {
+						break
+					}
+				}
+			}
+			if ferr := fn(tok); ferr != nil {
+				return ferr
+			}
+			s = s[len(tok.Raw):]
+		}
+
+		if errors.Is(err, io.EOF) {
+			if pending != "" {
+				return fn(Token{Kind: TokenEscape, Raw: pending})
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// PartialRune returns the length of an incomplete UTF-8 sequence at the end of s, or zero.
+func PartialRune(s string) int {
+	for i := 1; i <= utf8.UTFMax-1 && i <= len(s); i++ {
+		c := s[len(s)-i]
+		if c < 0x80 {
+			return 0
+		}
+		if utf8.RuneStart(c) {
+			if utf8.FullRuneInString(s[len(s)-i:]) {
+				return 0
+			}
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,253 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/droqsic/glint/internal/core"
+)
+
+type Attr uint16 // Attr is a bit set of SGR text attributes.
+
+const (
+	AttrBold      Attr = 1 << iota // AttrBold renders text with increased intensity
+	AttrDim                        // AttrDim renders text with decreased intensity
+	AttrItalic                     // AttrItalic renders text in italics
+	AttrUnderline                  // AttrUnderline underlines text
+	AttrBlink                      // AttrBlink makes text blink
+	AttrReverse                    // AttrReverse swaps foreground and background colors
+	AttrHidden                     // AttrHidden hides text while keeping its cells
+	AttrStrike                     // AttrStrike draws a line through text
+	AttrOverline                   // AttrOverline draws a line above text
+)
+
+// attrParams lists the SGR parameter enabling each attribute, in bit order.
+var attrParams = [...]string{"1", "2", "3", "4", "5", "7", "8", "9", "53"}
+
+// State tracks the graphic rendition and hyperlink that are active at a point in a stream.
+// The zero value is the terminal's default state.
+type State struct {
+	Fg, Bg     core.Color // Fg and Bg are the active foreground and background colors
+	Attrs      Attr       // Attrs is the set of active text attributes
+	Link       string     // Link is the URI of the open hyperlink, empty if none is open
+	LinkParams string     // LinkParams are the OSC 8 parameters of the open hyperlink, such as id=...
+}
+
+// Apply updates the state with the effect of tok. Tokens that do not affect rendition are ignored.
+func (s *State) Apply(tok Token) {
+	if tok.IsSGR() {
+		s.applySGR(tok.Params())
+		return
+	}
+	if params, uri, ok := tok.Hyperlink(); ok {
+		s.Link, s.LinkParams = uri, params
+		if uri == "" {
+			s.LinkParams = ""
+		}
+	}
+}
+
+// applySGR applies the parameters of an SGR sequence.
+func (s *State) applySGR(params string) {
+	if params == "" {
+		s.resetStyle()
+		return
+	}
+
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		// Colon separated sub-parameters carry extended colors and underline styles in one field.
+		if strings.IndexByte(field, ':') >= 0 {
+			s.applySubParams(strings.Split(field, ":"))
+			continue
+		}
+
+		n, _ := strconv.Atoi(field)
+		switch {
+		case n == 0:
+			s.resetStyle()
+		case n >= 1 && n <= 9:
+			s.Attrs |= attrFor(n)
+		case n == 21:
+			s.Attrs |= AttrUnderline
+		case n == 22:
+			s.Attrs &^= AttrBold | AttrDim
+		case n == 23:
+			s.Attrs &^= AttrItalic
+		case n == 24:
+			s.Attrs &^= AttrUnderline
+		case n == 25:
+			s.Attrs &^= AttrBlink
+		case n == 27:
+			s.Attrs &^= AttrReverse
+		case n == 28:
+			s.Attrs &^= AttrHidden
+		case n == 29:
+			s.Attrs &^= AttrStrike
+		case n == 53:
+			s.Attrs |= AttrOverline
+		case n == 55:
+			s.Attrs &^= AttrOverline
+		case n >= 30 && n <= 37:
+			s.Fg = core.Color{Kind: core.ColorANSI, Index: uint8(n - 30)}
+		case n >= 40 && n <= 47:
+			s.Bg = core.Color{Kind: core.ColorANSI, Index: uint8(n - 40)}
+		case n >= 90 && n <= 97:
+			s.Fg = core.Color{Kind: core.ColorANSI, Index: uint8(n - 90 + 8)}
+		case n >= 100 && n <= 107:
+			s.Bg = core.Color{Kind: core.ColorANSI, Index: uint8(n - 100 + 8)}
+		case n == 39:
+			s.Fg = core.Color{}
+		case n == 49:
+			s.Bg = core.Color{}
+		case n == 38 || n == 48:
+			color, used := extendedColor(fields[i+1:])
+			i += used
+			if n == 38 {
+				s.Fg = color
+			} else {
+				s.Bg = color
+			}
+		}
+	}
+}
+
+// applySubParams applies a colon separated SGR field such as 38:2::255:0:0 or 4:3.
+func (s *State) applySubParams(sub []string) {
+	switch sub[0] {
+	case "4":
+		if len(sub) > 1 && sub[1] == "0" {
+			s.Attrs &^= AttrUnderline
+		} else {
+			s.Attrs |= AttrUnderline
+		}
+	case "38", "48":
+		rest := sub[1:]
+		// The ITU form carries a color space id before the channels of an RGB color.
+		if len(rest) == 5 && rest[0] == "2" {
+			rest = append(rest[:1:1], rest[2:]...)
+		}
+		color, _ := extendedColor(rest)
+		if sub[0] == "38" {
+			s.Fg = color
+		} else {
+			s.Bg = color
+		}
+	}
+}
+
+// extendedColor decodes the arguments following a 38 or 48 parameter.
+// It returns the color and how many arguments were consumed.
+func extendedColor(args []string) (core.Color, int) {
+	if len(args) == 0 {
+		return core.Color{}, 0
+	}
+
+	switch args[0] {
+	case "5":
+		if len(args) < 2 {
+			return core.Color{}, len(args)
+		}
+		return core.Color{Kind: core.ColorANSI256, Index: channel(args[1])}, 2
+	case "2":
+		if len(args) < 4 {
+			return core.Color{}, len(args)
+		}
+		return core.Color{Kind: core.ColorRGB, R: channel(args[1]), G: channel(args[2]), B: channel(args[3])}, 4
+	}
+	return core.Color{}, 1
+}
+
+// channel parses a color component, clamping it to the range of a byte.
+func channel(s string) uint8 {
+	n, _ := strconv.Atoi(s)
+	return uint8(max(0, min(n, 255)))
+}
+
+// attrFor maps the SGR parameters 1 through 9 to their attribute.
+func attrFor(n int) Attr {
+	switch n {
+	case 1:
+		return AttrBold
+	case 2:
+		return AttrDim
+	case 3:
+		return AttrItalic
+	case 4:
+		return AttrUnderline
+	case 5, 6:
+		return AttrBlink
+	case 7:
+		return AttrReverse
+	case 8:
+		return AttrHidden
+	case 9:
+		return AttrStrike
+	}
+	return 0
+}
+
+// resetStyle clears colors and attributes but keeps the open hyperlink, as SGR 0 does.
+func (s *State) resetStyle() {
+	s.Fg, s.Bg, s.Attrs = core.Color{}, core.Color{}, 0
+}
+
+// Styled reports whether any color or attribute is active.
+func (s State) Styled() bool {
+	return s.Attrs != 0 || !s.Fg.IsDefault() || !s.Bg.IsDefault()
+}
+
+// SGR returns the sequence that establishes the state's colors and attributes from a reset terminal.
+// It returns an empty string when the state has no style.
+func (s State) SGR() string {
+	if !s.Styled() {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[")
+	sep := false
+	for i, p := range attrParams {
+		if s.Attrs&(1<<i) == 0 {
+			continue
+		}
+		if sep {
+			b.WriteByte(';')
+		}
+		b.WriteString(p)
+		sep = true
+	}
+	for _, c := range [...]struct {
+		color core.Color
+		bg    bool
+	}{{s.Fg, false}, {s.Bg, true}} {
+		if c.color.IsDefault() {
+			continue
+		}
+		if sep {
+			b.WriteByte(';')
+		}
+		b.WriteString(c.color.Params(c.bg))
+		sep = true
+	}
+	b.WriteByte('m')
+	return b.String()
+}
+
+// OpenLink returns the sequence that opens the state's hyperlink, or an empty string if none is open.
+func (s State) OpenLink() string {
+	if s.Link == "" {
+		return ""
+	}
+	return "\x1b]8;" + s.LinkParams + ";" + s.Link + ST
+}
+
+// CloseLink returns the sequence that closes the state's hyperlink, or an empty string if none is open.
+func (s State) CloseLink() string {
+	if s.Link == "" {
+		return ""
+	}
+	return "\x1b]8;;" + ST
+}
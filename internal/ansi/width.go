@@ -0,0 +1,145 @@
+package ansi
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const zwj = '\u200d' // zwj joins emoji into a single glyph
+
+// wide lists the code point ranges that occupy two terminal cells, sorted by start.
+// It covers the East Asian Wide and Fullwidth blocks and the emoji presentation ranges.
+var wide = [...][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec}, {0x23f0, 0x23f0},
+	{0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267f, 0x267f},
+	{0x2693, 0x2693}, {0x26a1, 0x26a1}, {0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5},
+	{0x26ce, 0x26ce}, {0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b}, {0x2728, 0x2728},
+	{0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27b0, 0x27b0}, {0x27bf, 0x27bf}, {0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55},
+	{0x2e80, 0x303e}, {0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19}, {0xfe30, 0xfe6f},
+	{0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4}, {0x17000, 0x18aff}, {0x1b000, 0x1b2ff},
+	{0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf}, {0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f251},
+	{0x1f300, 0x1f64f}, {0x1f680, 0x1f6ff}, {0x1f7e0, 0x1f7eb}, {0x1f90c, 0x1f9ff}, {0x1fa70, 0x1faff},
+	{0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
+
+// RuneWidth returns the number of terminal cells r occupies: 0 for control characters and
+// combining marks, 2 for wide characters, and 1 for everything else.
+func RuneWidth(r rune) int {
+	switch {
+	case isControl(r):
+		return 0
+	case r < 0x1100:
+		if unicode.In(r, unicode.Mn, unicode.Me) {
+			return 0
+		}
+		return 1
+	case r >= 0x1160 && r <= 0x11ff, r >= 0x200b && r <= 0x200f, r >= 0xfe00 && r <= 0xfe0f:
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+
+	lo, hi := 0, len(wide)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < wide[mid][0]:
+			hi = mid
+		case r > wide[mid][1]:
+			lo = mid + 1
+		default:
+			return 2
+		}
+	}
+	return 1
+}
+
+// StringWidth returns the number of terminal cells s occupies once escape sequences are removed.
+func StringWidth(s string) int {
+	width := 0
+	for len(s) > 0 {
+		tok, ok := Next(s)
+		if !ok {
+			break
+		}
+		if tok.Kind == TokenText {
+			width += textWidth(tok.Raw)
+		}
+		s = s[len(tok.Raw):]
+	}
+	return width
+}
+
+// textWidth returns the cell width of plain text, counting each grapheme cluster once.
+func textWidth(s string) int {
+	width, joined := 0, false
+	for _, r := range s {
+		if joined {
+			joined = false
+			continue
+		}
+		width += RuneWidth(r)
+		joined = r == zwj
+	}
+	return width
+}
+
+// Cell is one grapheme cluster of visible text together with the escape sequences that precede it.
+type Cell struct {
+	Prefix []Token // Prefix holds the escape sequences emitted before the text
+	Text   string  // Text is the grapheme cluster, a base rune followed by any zero-width runes
+	Width  int     // Width is the number of terminal cells Text occupies
+}
+
+// Cells splits s into cells. Escape sequences that follow the last cell are returned as trailing.
+// Unterminated escape sequences at the end of s are dropped.
+func Cells(s string) (cells []Cell, trailing []Token) {
+	var prefix []Token
+	joined := false
+
+	for len(s) > 0 {
+		tok, ok := Next(s)
+		if !ok {
+			break
+		}
+		s = s[len(tok.Raw):]
+
+		if tok.Kind != TokenText {
+			prefix = append(prefix, tok)
+			continue
+		}
+
+		text := tok.Raw
+		for len(text) > 0 {
+			r, size := utf8.DecodeRuneInString(text)
+			w := RuneWidth(r)
+
+			// Zero-width runes and runes following a joiner extend the previous cluster,
+			// unless an escape sequence or a control character separates them.
+			if n := len(cells); n > 0 && prefix == nil && !isControl(r) && !isControl(lastRune(cells[n-1].Text)) && (joined || w == 0) {
+				cells[n-1].Text += text[:size]
+			} else {
+				cells = append(cells, Cell{Prefix: prefix, Text: text[:size], Width: w})
+				prefix = nil
+			}
+
+			joined = r == zwj
+			text = text[size:]
+		}
+	}
+	return cells, prefix
+}
+
+// isControl reports whether r is a C0 or C1 control character.
+func isControl(r rune) bool {
+	return r < 0x20 || (r >= 0x7f && r < 0xa0)
+}
+
+// lastRune returns the last rune of s.
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
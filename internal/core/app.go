@@ -0,0 +1,126 @@
+package core
+
+import "strings"
+
+var (
+	appName     string // appName is the lower-cased application name, which selects its section of the config file
+	appColorKey string // appColorKey is the application scoped mode variable, such as MYTOOL_COLOR
+	appLevelKey string // appLevelKey is the application scoped level variable, such as MYTOOL_COLOR_LEVEL
+)
+
+// SetAppName registers the application whose scoped environment variables take precedence over the generic ones.
+// The name is upper-cased and any character that is not a letter or digit becomes an underscore, so "my-tool"
+// reads MY_TOOL_COLOR and MY_TOOL_COLOR_LEVEL. An empty name removes the registration. The cache is cleared
+// so the new variables are read on the next lookup.
+func SetAppName(name string) {
+	prefix := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+
+	envMutex.Lock()
+	defer envMutex.Unlock()
+
+	appName = strings.ToLower(name)
+	appColorKey, appLevelKey = "", ""
+	if prefix != "" {
+		appColorKey, appLevelKey = prefix+"_COLOR", prefix+"_COLOR_LEVEL"
+	}
+
+	for k := range envCache {
+		delete(envCache, k)
+	}
+
+	envConfig = nil
+	envInit.Store(false)
+}
+
+// AppEnvKeys returns the names of the application scoped variables, or empty strings if no application is registered.
+func AppEnvKeys() (colorKey, levelKey string) {
+	envMutex.RLock()
+	defer envMutex.RUnlock()
+
+	return appColorKey, appLevelKey
+}
+
+// AppColorLevel evaluates the application scoped variables. The mode variable accepts never, always, auto
+// or a level, and the level variable accepts a level. A mode of never wins, then an explicit level, then always,
+// which enables color at the level the generic variables describe, ignoring NO_COLOR, with at least 16 colors.
+// It returns false for ok when the variables are unset, set to auto, or malformed. Forced reports that the mode
+// variable is never, always or a level, which decides even if the output is not a terminal, while a level variable
+// alone only chooses the level of a terminal.
+func AppColorLevel() (level Level, forced, ok bool) {
+	if colorKey, _ := AppEnvKeys(); colorKey == "" {
+		return LevelNone, false, false
+	}
+
+	d := cachedDetector()
+	level, _, ok = d.appColorLevel()
+	return level, ok && modeDecides(d.getenv(d.colorKey)), ok
+}
+
+// modeDecides reports whether a mode setting is never, always or a level rather than auto or malformed.
+func modeDecides(mode string) bool {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "never", "no", "none", "off", "false", "0", "always", "yes", "force", "on", "true", "1":
+		return true
+	}
+	_, ok := ParseLevel(mode)
+	return ok
+}
+
+// appColorLevel evaluates the application scoped variables like AppColorLevel and also returns the deciding rule.
+func (d *detector) appColorLevel() (Level, string, bool) {
+	if d.colorKey == "" {
+		return LevelNone, "", false
+	}
+
+	return resolveSetting(d.colorKey, d.getenv(d.colorKey), d.levelKey, d.getenv(d.levelKey), func() Level {
+		level, _ := d.genericColorLevel(false)
+		return level
+	})
+}
+
+// resolveSetting evaluates a mode and a level setting, which the rule reports as modeName=mode or levelName=level.
+// A mode of never wins, then a valid level, then a mode of always, which returns at least Level16 of the fallback level,
+// then a mode naming a level. It returns false for ok if neither setting decides.
+func resolveSetting(modeName, mode, levelName, level string, fallback func() Level) (Level, string, bool) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "never", "no", "none", "off", "false", "0":
+		return LevelNone, modeName + "=" + mode, true
+	}
+
+	if parsed, ok := ParseLevel(level); ok {
+		return parsed, levelName + "=" + level, true
+	}
+
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "always", "yes", "force", "on", "true", "1":
+		return max(fallback(), Level16), modeName + "=" + mode, true
+	}
+
+	if parsed, ok := ParseLevel(mode); ok {
+		return parsed, modeName + "=" + mode, true
+	}
+	return LevelNone, "", false
+}
+
+// ParseLevel parses a color level name: none, 16, 256 or truecolor, with the aliases 0, ansi, ansi256, 24bit and 16m.
+func ParseLevel(s string) (Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "none", "0":
+		return LevelNone, true
+	case "16", "ansi", "basic":
+		return Level16, true
+	case "256", "ansi256":
+		return Level256, true
+	case "truecolor", "24bit", "16m":
+		return LevelTrue, true
+	}
+	return LevelNone, false
+}
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IsDark reports whether the color is perceived as dark, using the relative brightness of its channels.
+func (c Color) IsDark() bool {
+	r, g, b := c.RGB()
+	return 299*int(r)+587*int(g)+114*int(b) < 128*1000
+}
+
+// BackgroundFromEnv inspects COLORFGBG, set by rxvt, Konsole and others to "fg;bg" palette indexes,
+// and reports whether the background is dark. It returns false for ok if the variable is missing or malformed.
+func BackgroundFromEnv() (dark bool, ok bool) {
+	value := GetEnvCache(EnvColorFgBg)
+	if value == "" {
+		return false, false
+	}
+
+	// The background is the last field, some terminals insert an extra "default" field in between.
+	field := value[strings.LastIndexByte(value, ';')+1:]
+	index, err := strconv.Atoi(field)
+	if err != nil || index < 0 || index > 15 {
+		return false, false
+	}
+
+	// Indexes 7 (white) and 9 through 15 (bright colors) are light, the rest are dark.
+	return index != 7 && index < 9, true
+}
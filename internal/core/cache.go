@@ -3,6 +3,7 @@ package core
 import (
 	"os"
 	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -23,12 +24,19 @@ const (
 	EnvCustomColor16  = "COLOR_16"             // Custom flag to force 16 color mode
 	EnvCustomColor256 = "COLOR_256"            // Custom flag to force 256 color mode
 	EnvCustomColor24  = "COLOR_24"             // Custom flag to force 24-bit truecolor mode
+	EnvColorFgBg      = "COLORFGBG"            // Foreground and background palette indexes (e.g., 15;0)
+	EnvXDGConfigHome  = "XDG_CONFIG_HOME"      // Base directory of user configuration files
+	EnvHome           = "HOME"                 // Home directory, holds the user configuration on Unix and macOS
+	EnvAppData        = "APPDATA"              // Directory of user configuration files on Windows
+	EnvInsideEmacs    = "INSIDE_EMACS"         // Set by Emacs in its shell and terminal buffers (e.g., 29.1,comint)
+	EnvVimTerminal    = "VIM_TERMINAL"         // Set by Vim in :terminal windows to the Vim version
+	EnvNvim           = "NVIM"                 // Set by Neovim in :terminal buffers to its server address
 )
 
 var (
 	envCache map[string]string // envCache stores environment variable values to avoid repeated system calls
 	envMutex sync.RWMutex      // envMutex protects concurrent access to the environment cache
-	envInit  bool              // envInit tracks whether the cache has been initialized
+	envInit  atomic.Bool       // envInit tracks whether the cache has been initialized
 
 	// knownKeys is the list of environment variables to cache
 	knownKeys = []string{
@@ -49,6 +57,13 @@ var (
 		EnvCustomColor16,
 		EnvCustomColor256,
 		EnvCustomColor24,
+		EnvColorFgBg,
+		EnvXDGConfigHome,
+		EnvHome,
+		EnvAppData,
+		EnvInsideEmacs,
+		EnvVimTerminal,
+		EnvNvim,
 	}
 )
 
@@ -60,14 +75,14 @@ func init() {
 // SetEnvCache populates the environment variable cache if it hasn't been initialized.
 // This function is thread-safe and will only initialize the cache once.
 func SetEnvCache() {
-	if envInit {
+	if envInit.Load() {
 		return
 	}
 
 	envMutex.Lock()
 	defer envMutex.Unlock()
 
-	if envInit {
+	if envInit.Load() {
 		return
 	}
 
@@ -75,13 +90,25 @@ func SetEnvCache() {
 		envCache[key] = os.Getenv(key)
 	}
 
-	envInit = true
+	for _, key := range [...]string{appColorKey, appLevelKey} {
+		if key != "" {
+			envCache[key] = os.Getenv(key)
+		}
+	}
+
+	envConfig = loadConfig(configPathLocked())
+	envInit.Store(true)
 }
 
 // GetEnvCache retrieves an environment variable value from the cache.
-// If the cache hasn't been initialized, it will initialize it first.
+// If the cache hasn't been initialized, it will initialize it first. A fake terminal installed with SetFake
+// replaces the cache with its own environment.
 func GetEnvCache(name string) string {
-	if !envInit {
+	if f := fake.Load(); f != nil {
+		return f.Env[name]
+	}
+
+	if !envInit.Load() {
 		SetEnvCache()
 	}
 
@@ -101,5 +128,6 @@ func ClearCache() {
 		delete(envCache, k)
 	}
 
-	envInit = false
+	envConfig = nil
+	envInit.Store(false)
 }
@@ -0,0 +1,158 @@
+package core
+
+import "strconv"
+
+type ColorKind uint8 // ColorKind identifies how a Color value is encoded.
+
+const (
+	ColorDefault ColorKind = iota // ColorDefault indicates the terminal's default color
+	ColorANSI                     // ColorANSI indicates one of the 16 basic ANSI colors
+	ColorANSI256                  // ColorANSI256 indicates an entry of the 256 color palette
+	ColorRGB                      // ColorRGB indicates a 24-bit RGB color
+)
+
+// Color represents a single terminal color in any of the encodings a terminal understands.
+// The zero value is the terminal's default color.
+type Color struct {
+	Kind    ColorKind // Kind selects which of the remaining fields are meaningful
+	Index   uint8     // Index is the palette index for ColorANSI (0-15) and ColorANSI256 (0-255)
+	R, G, B uint8     // R, G and B are the channel values for ColorRGB
+}
+
+// IsDefault reports whether the color is the terminal's default color.
+func (c Color) IsDefault() bool {
+	return c.Kind == ColorDefault
+}
+
+// Params returns the SGR parameters that select the color as foreground or background.
+// It returns the parameter that restores the default color when the color is the default.
+func (c Color) Params(background bool) string {
+	base := 30
+	if background {
+		base = 40
+	}
+
+	switch c.Kind {
+	case ColorANSI:
+		if c.Index < 8 {
+			return strconv.Itoa(base + int(c.Index))
+		}
+		return strconv.Itoa(base + 60 + int(c.Index&7))
+	case ColorANSI256:
+		return strconv.Itoa(base+8) + ";5;" + strconv.Itoa(int(c.Index))
+	case ColorRGB:
+		return strconv.Itoa(base+8) + ";2;" + strconv.Itoa(int(c.R)) + ";" + strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B))
+	default:
+		return strconv.Itoa(base + 9)
+	}
+}
+
+// basicRGB holds the xterm default values of the 16 basic ANSI colors.
+var basicRGB = [16][3]uint8{
+	{0x00, 0x00, 0x00}, {0xcd, 0x00, 0x00}, {0x00, 0xcd, 0x00}, {0xcd, 0xcd, 0x00},
+	{0x00, 0x00, 0xee}, {0xcd, 0x00, 0xcd}, {0x00, 0xcd, 0xcd}, {0xe5, 0xe5, 0xe5},
+	{0x7f, 0x7f, 0x7f}, {0xff, 0x00, 0x00}, {0x00, 0xff, 0x00}, {0xff, 0xff, 0x00},
+	{0x5c, 0x5c, 0xff}, {0xff, 0x00, 0xff}, {0x00, 0xff, 0xff}, {0xff, 0xff, 0xff},
+}
+
+// cubeLevels holds the channel values of the 6x6x6 color cube in the 256 color palette.
+var cubeLevels = [6]uint8{0x00, 0x5f, 0x87, 0xaf, 0xd7, 0xff}
+
+// BasicIndex reports whether the color is one of the 16 basic colors, either directly or through
+// the first 16 entries of the 256 color palette, and returns its index.
+func (c Color) BasicIndex() (uint8, bool) {
+	switch {
+	case c.Kind == ColorANSI:
+		return c.Index & 15, true
+	case c.Kind == ColorANSI256 && c.Index < 16:
+		return c.Index, true
+	}
+	return 0, false
+}
+
+// RGB returns the red, green and blue components of the color.
+// Basic colors use the xterm defaults, and the default color is reported as black.
+func (c Color) RGB() (r, g, b uint8) {
+	if i, ok := c.BasicIndex(); ok {
+		return basicRGB[i][0], basicRGB[i][1], basicRGB[i][2]
+	}
+
+	switch c.Kind {
+	case ColorANSI256:
+		if c.Index >= 232 {
+			v := 8 + 10*(c.Index-232)
+			return v, v, v
+		}
+		i := c.Index - 16
+		return cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]
+	case ColorRGB:
+		return c.R, c.G, c.B
+	}
+	return 0, 0, 0
+}
+
+// Convert returns the closest color a terminal with the given color level can display.
+// Colors the level already supports are returned unchanged, and LevelNone yields the default color.
+func (c Color) Convert(level Level) Color {
+	switch {
+	case c.Kind == ColorDefault || level >= LevelTrue:
+		return c
+	case level <= LevelNone:
+		return Color{}
+	case level == Level256:
+		if c.Kind == ColorRGB {
+			return Color{Kind: ColorANSI256, Index: nearest256(c.R, c.G, c.B)}
+		}
+		return c
+	}
+
+	if i, ok := c.BasicIndex(); ok {
+		return Color{Kind: ColorANSI, Index: i}
+	}
+	r, g, b := c.RGB()
+	return Color{Kind: ColorANSI, Index: nearest16(r, g, b)}
+}
+
+// nearest256 returns the entry of the 6x6x6 cube or the grayscale ramp closest to the given color.
+func nearest256(r, g, b uint8) uint8 {
+	cube := func(v uint8) uint8 {
+		switch {
+		case v < 48:
+			return 0
+		case v < 115:
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	ri, gi, bi := cube(r), cube(g), cube(b)
+	cubeIndex := 16 + 36*ri + 6*gi + bi
+	cubeDist := distance(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	avg := (int(r) + int(g) + int(b)) / 3
+	grayIndex := uint8(23)
+	if avg < 238 {
+		grayIndex = uint8(max(avg-3, 0) / 10)
+	}
+	v := 8 + 10*grayIndex
+	if distance(r, g, b, v, v, v) < cubeDist {
+		return 232 + grayIndex
+	}
+	return cubeIndex
+}
+
+// nearest16 returns the basic color closest to the given color.
+func nearest16(r, g, b uint8) uint8 {
+	best, bestDist := uint8(0), -1
+	for i, c := range basicRGB {
+		if d := distance(r, g, b, c[0], c[1], c[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = uint8(i), d
+		}
+	}
+	return best
+}
+
+// distance returns the squared distance between two colors, weighted for how the eye perceives each channel.
+func distance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return 2*dr*dr + 4*dg*dg + 3*db*db
+}
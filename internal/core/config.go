@@ -0,0 +1,172 @@
+package core
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// configSection holds the settings of the config file for all applications or for one.
+type configSection struct {
+	mode  string            // mode is auto, always, never or a level
+	level string            // level is none, 16, 256 or truecolor
+	terms map[string]string // terms maps TERM values to the level used on those terminals
+}
+
+// config is a parsed config file.
+type config struct {
+	global configSection             // global applies to every application
+	apps   map[string]*configSection // apps holds the per-application sections by lower-cased name
+}
+
+var envConfig *config // envConfig is the config file loaded with the environment cache, nil if there is none
+
+// ConfigPath returns the location of the config file, $XDG_CONFIG_HOME/glint/config or the platform's user config directory.
+// It returns an empty string if no config directory is known.
+func ConfigPath() string {
+	SetEnvCache()
+
+	envMutex.RLock()
+	defer envMutex.RUnlock()
+
+	return configPathLocked()
+}
+
+// configPathLocked returns the location of the config file. The caller must hold envMutex.
+func configPathLocked() string {
+	return configPathFrom(func(key string) string {
+		return envCache[key]
+	})
+}
+
+// configPathFrom returns the location of the config file in the environment read by getenv.
+// It follows os.UserConfigDir but never consults the process environment, and returns an empty string
+// if the environment names no config directory.
+func configPathFrom(getenv func(string) string) string {
+	dir := getenv(EnvXDGConfigHome)
+	if dir == "" {
+		switch runtime.GOOS {
+		case "windows":
+			dir = getenv(EnvAppData)
+		case "darwin", "ios":
+			if home := getenv(EnvHome); home != "" {
+				dir = filepath.Join(home, "Library", "Application Support")
+			}
+		default:
+			if home := getenv(EnvHome); home != "" {
+				dir = filepath.Join(home, ".config")
+			}
+		}
+	}
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "glint", "config")
+}
+
+// loadConfig reads and parses the config file at path, returning nil if it can't be read.
+func loadConfig(path string) *config {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	cfg := &config{apps: make(map[string]*configSection)}
+	section := &cfg.global
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			name, _, _ := strings.Cut(line[1:], "]")
+			name = strings.ToLower(strings.Trim(strings.TrimSpace(name), `"`))
+			if name == "" || name == "global" {
+				section = &cfg.global
+				continue
+			}
+			if cfg.apps[name] == nil {
+				cfg.apps[name] = &configSection{}
+			}
+			section = cfg.apps[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = configValue(value)
+
+		switch {
+		case key == "mode":
+			section.mode = value
+		case key == "level":
+			section.level = value
+		case strings.HasPrefix(key, "term."):
+			if section.terms == nil {
+				section.terms = make(map[string]string)
+			}
+			section.terms[strings.Trim(key[len("term."):], `"`)] = value
+		}
+	}
+	return cfg
+}
+
+// configValue trims a value, removes a trailing comment and the quotes around it.
+func configValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.IndexAny(value, "#;"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// configColorLevel evaluates the config file, the section of the application registered with SetAppName first,
+// then the global settings. Within a section a mode of never wins, then the level for the current TERM,
+// then the level, then a mode of always or a level.
+func (d *detector) configColorLevel() (Level, string, bool) {
+	cfg, name := d.config, d.app
+	if cfg == nil {
+		return LevelNone, "", false
+	}
+
+	if section := cfg.apps[name]; name != "" && section != nil {
+		if level, rule, ok := section.resolve(d); ok {
+			return level, "config [" + name + "] " + rule, true
+		}
+	}
+	if level, rule, ok := cfg.global.resolve(d); ok {
+		return level, "config " + rule, true
+	}
+	return LevelNone, "", false
+}
+
+// resolve evaluates the settings of one section.
+func (s *configSection) resolve(d *detector) (Level, string, bool) {
+	levelName, level := "level", s.level
+	if term := d.getenv(EnvTerm); term != "" {
+		if value, ok := s.terms[term]; ok {
+			levelName, level = "term."+term, value
+		}
+	}
+	return resolveSetting("mode", s.mode, levelName, level, func() Level {
+		level, _ := d.heuristicColorLevel()
+		return level
+	})
+}
@@ -0,0 +1,25 @@
+package core
+
+// detector evaluates the detection rules against one environment.
+type detector struct {
+	getenv   func(key string) string // getenv looks up a variable, returning an empty string if it is unset
+	config   *config                 // config is the parsed config file, nil if there is none
+	terminal *Level                  // terminal replaces the heuristics with a fixed level when not nil
+	app      string                  // app is the lower-cased application name registered with SetAppName
+	colorKey string                  // colorKey is the application scoped mode variable
+	levelKey string                  // levelKey is the application scoped level variable
+}
+
+// cachedDetector returns a detector over the environment cache, initializing the cache if needed.
+func cachedDetector() *detector {
+	if f := fake.Load(); f != nil {
+		return fakeDetector(f)
+	}
+
+	SetEnvCache()
+
+	envMutex.RLock()
+	defer envMutex.RUnlock()
+
+	return &detector{getenv: GetEnvCache, config: envConfig, app: appName, colorKey: appColorKey, levelKey: appLevelKey}
+}
@@ -0,0 +1,110 @@
+package core
+
+import "strings"
+
+// ciProviders maps the variables CI services set to the names reported by DetectCI, in order of precedence.
+var ciProviders = []struct {
+	env  string // env is set by the provider
+	name string // name identifies the provider
+}{
+	{"GITHUB_ACTIONS", "github-actions"},
+	{"GITLAB_CI", "gitlab"},
+	{"BUILDKITE", "buildkite"},
+	{"CIRCLECI", "circleci"},
+	{"TRAVIS", "travis"},
+	{"TF_BUILD", "azure-pipelines"},
+	{"JENKINS_URL", "jenkins"},
+	{"TEAMCITY_VERSION", "teamcity"},
+	{"BITBUCKET_BUILD_NUMBER", "bitbucket"},
+	{"APPVEYOR", "appveyor"},
+	{"CODEBUILD_BUILD_ID", "codebuild"},
+	{"DRONE", "drone"},
+	{"WOODPECKER", "woodpecker"},
+}
+
+// DetectCI names the CI service the process runs on, "unknown" if only CI is set, or an empty string outside CI.
+func DetectCI(getenv func(string) string) string {
+	for _, provider := range ciProviders {
+		if getenv(provider.env) != "" {
+			return provider.name
+		}
+	}
+	if getenv(EnvCI) != "" {
+		return "unknown"
+	}
+	return ""
+}
+
+// DetectMultiplexer names the terminal multiplexer the process runs in, tmux, screen or zellij,
+// or returns an empty string if there is none.
+func DetectMultiplexer(getenv func(string) string) string {
+	switch {
+	case getenv("TMUX") != "":
+		return "tmux"
+	case getenv("ZELLIJ") != "":
+		return "zellij"
+	case getenv("STY") != "":
+		return "screen"
+	}
+
+	// Nested sessions and sudo may drop the variables but keep TERM
+	switch term := getenv(EnvTerm); {
+	case strings.HasPrefix(term, "tmux"):
+		return "tmux"
+	case strings.HasPrefix(term, "screen"):
+		return "screen"
+	}
+	return ""
+}
+
+const (
+	EmbeddingEmacsShell = "emacs-shell" // EmbeddingEmacsShell is M-x shell or another comint buffer, which renders 16 colors with TERM=dumb
+	EmbeddingEmacsTerm  = "emacs-term"  // EmbeddingEmacsTerm is M-x term or ansi-term, with TERM=eterm-color
+	EmbeddingEmacsVterm = "emacs-vterm" // EmbeddingEmacsVterm is the vterm package, a full terminal described by TERM
+	EmbeddingEmacsEat   = "emacs-eat"   // EmbeddingEmacsEat is the eat package, with TERM=eat-truecolor or a lesser variant
+	EmbeddingEmacsTramp = "emacs-tramp" // EmbeddingEmacsTramp is a remote command run by TRAMP, which shows no colors
+	EmbeddingEmacs      = "emacs"       // EmbeddingEmacs is any other Emacs buffer, such as M-x compile
+	EmbeddingVim        = "vim"         // EmbeddingVim is a Vim :terminal window, described by TERM
+	EmbeddingNeovim     = "neovim"      // EmbeddingNeovim is a Neovim :terminal buffer, described by TERM
+)
+
+// DetectEmbedding names the editor hosting the terminal, with one of the Embedding constants, or returns an empty
+// string if there is none. INSIDE_EMACS holds the Emacs version and the mode, such as "29.1,comint" or
+// "29.1,term:0.96", while vterm sets it to "vterm" alone.
+func DetectEmbedding(getenv func(string) string) string {
+	if value := getenv(EnvInsideEmacs); value != "" {
+		for _, field := range strings.Split(value, ",") {
+			mode, _, _ := strings.Cut(field, ":")
+			switch mode {
+			case "comint":
+				return EmbeddingEmacsShell
+			case "term":
+				return EmbeddingEmacsTerm
+			case "vterm":
+				return EmbeddingEmacsVterm
+			case "eat":
+				return EmbeddingEmacsEat
+			case "tramp":
+				return EmbeddingEmacsTramp
+			}
+		}
+		return EmbeddingEmacs
+	}
+
+	switch {
+	case getenv(EnvNvim) != "":
+		return EmbeddingNeovim
+	case getenv(EnvVimTerminal) != "":
+		return EmbeddingVim
+	}
+	return ""
+}
+
+// KnownKeys returns the environment variables detection reads, including the application scoped ones.
+func KnownKeys() []string {
+	keys := append([]string(nil), knownKeys...)
+	if colorKey, levelKey := AppEnvKeys(); colorKey != "" {
+		keys = append(keys, colorKey, levelKey)
+	}
+	return keys
+}
@@ -0,0 +1,38 @@
+package core
+
+import "sync/atomic"
+
+// Fake describes a simulated terminal that replaces the process environment and the terminal itself during tests.
+// It is installed by the glinttest package.
+type Fake struct {
+	Env        map[string]string // Env replaces the process environment, the config file is not read
+	Level      Level             // Level replaces the result of the terminal heuristics
+	TTY        bool              // TTY reports every writer as a terminal
+	Columns    int               // Columns is the reported terminal width
+	Rows       int               // Rows is the reported terminal height
+	Background int8              // Background is the reported background, with the values of glint.Background
+}
+
+// fake holds the installed fake terminal, nil when the real one is used.
+var fake atomic.Pointer[Fake]
+
+// SetFake installs f in place of the real terminal, or removes the fake terminal if f is nil.
+// It returns the previously installed fake so it can be restored.
+func SetFake(f *Fake) *Fake {
+	return fake.Swap(f)
+}
+
+// ActiveFake returns the installed fake terminal, or nil if the real one is used.
+func ActiveFake() *Fake {
+	return fake.Load()
+}
+
+// fakeDetector returns a detector over the environment of f, which stands in for the terminal heuristics with f.Level.
+func fakeDetector(f *Fake) *detector {
+	envMutex.RLock()
+	defer envMutex.RUnlock()
+
+	return &detector{getenv: func(key string) string {
+		return f.Env[key]
+	}, terminal: &f.Level, app: appName, colorKey: appColorKey, levelKey: appLevelKey}
+}
@@ -1,5 +1,7 @@
 package core
 
+import "strings"
+
 type Level int8 // Level represents the color support capability of a terminal.
 
 const (
@@ -25,83 +27,163 @@ func (l Level) String() string {
 	}
 }
 
-// terminalColorLevel determines the color support level of the terminal based on environment variables and terminal type.
-// This is an internal function used by DetectColorLevel.
+// TerminalColorLevel determines the color support level of the terminal based on environment variables and terminal type.
+// Application scoped variables registered with SetAppName take precedence over the generic ones.
 func TerminalColorLevel() Level {
-	SetEnvCache()
+	level, _ := ExplainColorLevel()
+	return level
+}
+
+// ExplainColorLevel determines the color support level like TerminalColorLevel and also returns the rule that decided it,
+// such as "NO_COLOR", "TERM=xterm-256color" or "config [mytool] level=256".
+func ExplainColorLevel() (Level, string) {
+	return cachedDetector().explain()
+}
+
+// ExplainColorLevelEnv determines the color support level like ExplainColorLevel from an explicit environment
+// in the key=value form of os.Environ, ignoring the process environment and the cache. The config file is read
+// from the location the given environment points to, and skipped if it names no config directory.
+// Later entries win over earlier ones with the same key.
+func ExplainColorLevelEnv(env []string) (Level, string) {
+	values := make(map[string]string, len(env))
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			values[key] = value
+		}
+	}
+	getenv := func(key string) string {
+		return values[key]
+	}
 
+	envMutex.RLock()
+	d := detector{getenv: getenv, app: appName, colorKey: appColorKey, levelKey: appLevelKey}
+	envMutex.RUnlock()
+
+	d.config = loadConfig(configPathFrom(getenv))
+	return d.explain()
+}
+
+// explain evaluates the application scoped variables, then the generic ones.
+func (d *detector) explain() (Level, string) {
+	if level, rule, ok := d.appColorLevel(); ok {
+		return level, rule
+	}
+	return d.genericColorLevel(true)
+}
+
+// genericColorLevel determines the color support level from the generic environment variables, then the config file,
+// then the terminal heuristics. NO_COLOR is only consulted if noColor is set.
+func (d *detector) genericColorLevel(noColor bool) (Level, string) {
 	// NO_COLOR environment variable takes precedence over everything else
-	if GetEnvCache(EnvNoColor) != "" {
-		return LevelNone
+	if noColor && d.getenv(EnvNoColor) != "" {
+		return LevelNone, EnvNoColor
 	}
 
 	// Check for explicit color forcing environment variables
-	if GetEnvCache(EnvForceColor) != "" {
-		return LevelTrue
+	if d.getenv(EnvForceColor) != "" {
+		return LevelTrue, EnvForceColor
 	}
 
 	// Check for custom color level environment variables
-	if GetEnvCache(EnvCustomColor24) != "" {
-		return LevelTrue
+	if d.getenv(EnvCustomColor24) != "" {
+		return LevelTrue, EnvCustomColor24
 	}
-	if GetEnvCache(EnvCustomColor256) != "" {
-		return Level256
+	if d.getenv(EnvCustomColor256) != "" {
+		return Level256, EnvCustomColor256
 	}
-	if GetEnvCache(EnvCustomColor16) != "" {
-		return Level16
+	if d.getenv(EnvCustomColor16) != "" {
+		return Level16, EnvCustomColor16
+	}
+
+	// The config file ranks below the environment variables and above the heuristics
+	if level, rule, ok := d.configColorLevel(); ok {
+		return level, rule
+	}
+
+	return d.heuristicColorLevel()
+}
+
+// heuristicColorLevel determines the color support level from the variables describing the terminal.
+func (d *detector) heuristicColorLevel() (Level, string) {
+	if d.terminal != nil {
+		return *d.terminal, "fake terminal"
+	}
+
+	// Editors hosting the terminal decide first, since they pass on variables that don't describe them
+	switch embedding := DetectEmbedding(d.getenv); embedding {
+	case EmbeddingEmacsShell:
+		return Level16, EnvInsideEmacs + "=" + d.getenv(EnvInsideEmacs)
+	case EmbeddingEmacsTramp:
+		return LevelNone, EnvInsideEmacs + "=" + d.getenv(EnvInsideEmacs)
+	}
+
+	// TERM=dumb declares a terminal without escape sequences, whatever else is set
+	if d.getenv(EnvTerm) == "dumb" {
+		return LevelNone, EnvTerm + "=dumb"
+	}
+
+	// Windows Terminal supports 24-bit color whatever TERM says, which matters inside WSL
+	if d.getenv(EnvWTSession) != "" {
+		return LevelTrue, EnvWTSession
+	}
+	if d.getenv(EnvWTProfileID) != "" {
+		return LevelTrue, EnvWTProfileID
 	}
 
 	// Check COLORTERM for truecolor or 256 color support
-	switch GetEnvCache(EnvColorTerm) {
+	switch value := d.getenv(EnvColorTerm); value {
 	case "truecolor", "24bit":
-		return LevelTrue
+		return LevelTrue, EnvColorTerm + "=" + value
 	case "256color":
-		return Level256
+		return Level256, EnvColorTerm + "=" + value
 	}
 
 	// Check TERM for color support information
-	switch GetEnvCache(EnvTerm) {
+	switch value := d.getenv(EnvTerm); value {
 	case "xterm-256color", "screen-256color", "tmux-256color", "rxvt-256color":
-		return Level256
+		return Level256, EnvTerm + "=" + value
 	case "xterm", "screen", "tmux", "rxvt":
-		return Level16
-	case "dumb":
-		return LevelNone
+		return Level16, EnvTerm + "=" + value
+	case "eat-truecolor":
+		return LevelTrue, EnvTerm + "=" + value
+	case "eat-256color":
+		return Level256, EnvTerm + "=" + value
+	case "eat-color", "eterm-color":
+		return Level16, EnvTerm + "=" + value
 	}
 
 	// Check for specific terminal environments
-	if GetEnvCache(EnvWTSession) != "" || GetEnvCache(EnvWTProfileID) != "" {
-		return LevelTrue
+	if d.getenv(EnvANSICON) != "" {
+		return Level256, EnvANSICON
 	}
-
-	if GetEnvCache(EnvANSICON) != "" || GetEnvCache(EnvConEmuANSI) == "ON" {
-		return Level256
+	if d.getenv(EnvConEmuANSI) == "ON" {
+		return Level256, EnvConEmuANSI + "=ON"
 	}
 
-	if GetEnvCache(EnvTermProgram) == "iTerm.app" {
-		return LevelTrue
+	if d.getenv(EnvTermProgram) == "iTerm.app" {
+		return LevelTrue, EnvTermProgram + "=iTerm.app"
 	}
 
 	// CI environments typically support at least basic colors
-	if GetEnvCache(EnvCI) != "" {
-		return Level16
+	if d.getenv(EnvCI) != "" {
+		return Level16, EnvCI
 	}
 
 	// Termux on Android supports 256 colors
-	if GetEnvCache(EnvTermuxVersion) != "" {
-		return Level256
+	if d.getenv(EnvTermuxVersion) != "" {
+		return Level256, EnvTermuxVersion
 	}
 
 	// WSL typically supports 256 colors
-	if GetEnvCache(EnvWSLEnv) != "" {
-		return Level256
+	if d.getenv(EnvWSLEnv) != "" {
+		return Level256, EnvWSLEnv
 	}
 
 	// SSH connections typically support 256 colors
-	if GetEnvCache(EnvSSHConnection) != "" {
-		return Level256
+	if d.getenv(EnvSSHConnection) != "" {
+		return Level256, EnvSSHConnection
 	}
 
 	// Default to basic 16 colors if we can't determine anything more specific
-	return Level16
+	return Level16, "default"
 }
@@ -0,0 +1,8 @@
+package platform
+
+import "errors"
+
+var (
+	ErrQueryUnsupported = errors.New("terminal queries are not supported on this platform") // ErrQueryUnsupported is returned where the controlling terminal can't be queried
+	ErrQueryTimeout     = errors.New("terminal did not answer the query in time")           // ErrQueryTimeout is returned when the terminal doesn't reply before the deadline
+)
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package platform
+
+import "time"
+
+// QueryTerminal is not supported on this platform and always returns ErrQueryUnsupported.
+func QueryTerminal(query string, timeout time.Duration) (string, error) {
+	return "", ErrQueryUnsupported
+}
@@ -0,0 +1,88 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package platform
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/droqsic/glint/internal/ansi"
+	"golang.org/x/sys/unix"
+)
+
+// QueryTerminal writes query to the controlling terminal and returns the terminal's reply.
+// A primary device attributes request is sent after the query. Every terminal answers it, and it is answered in order,
+// so its reply marks the end of the answer to query, and a terminal that ignores query is detected without waiting for the timeout.
+// The terminal is put in raw mode while waiting and restored afterwards.
+func QueryTerminal(query string, timeout time.Duration) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return "", err
+	}
+	defer tty.Close()
+
+	fd := int(tty.Fd())
+	saved, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return "", err
+	}
+
+	raw := *saved
+	raw.Lflag &^= unix.ICANON | unix.ECHO
+	raw.Cc[unix.VMIN] = 0
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return "", err
+	}
+	defer unix.IoctlSetTermios(fd, ioctlSetTermios, saved)
+
+	if _, err := tty.WriteString(query + "\x1b[c"); err != nil {
+		return "", err
+	}
+	return readReply(fd, time.Now().Add(timeout))
+}
+
+// readReply reads from fd until a device attributes report arrives or the deadline passes.
+// It returns everything received before the report.
+func readReply(fd int, deadline time.Time) (string, error) {
+	var reply []byte
+	buf := make([]byte, 256)
+
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return string(reply), ErrQueryTimeout
+		}
+
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(remaining/time.Millisecond)+1)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return string(reply), err
+		}
+		if n == 0 {
+			continue
+		}
+
+		m, err := unix.Read(fd, buf)
+		if err != nil && !errors.Is(err, unix.EINTR) && !errors.Is(err, unix.EAGAIN) {
+			return string(reply), err
+		}
+		reply = append(reply, buf[:max(m, 0)]...)
+
+		for s := string(reply); len(s) > 0; {
+			tok, ok := ansi.Next(s)
+			if !ok {
+				break
+			}
+			if ansi.IsDeviceAttributes(tok) {
+				return string(reply[:len(reply)-len(s)]), nil
+			}
+			s = s[len(tok.Raw):]
+		}
+	}
+}
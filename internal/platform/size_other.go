@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly,!windows
+
+package platform
+
+// TerminalSize is not supported on this platform and always returns ErrQueryUnsupported.
+func TerminalSize(fd uintptr) (columns, rows int, err error) {
+	return 0, 0, ErrQueryUnsupported
+}
@@ -0,0 +1,15 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package platform
+
+import "golang.org/x/sys/unix"
+
+// TerminalSize returns the number of columns and rows of the terminal open on fd.
+func TerminalSize(fd uintptr) (columns, rows int, err error) {
+	ws, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
@@ -0,0 +1,15 @@
+//go:build windows
+// +build windows
+
+package platform
+
+import "golang.org/x/sys/windows"
+
+// TerminalSize returns the number of columns and rows of the visible window of the console open on fd.
+func TerminalSize(fd uintptr) (columns, rows int, err error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0, 0, err
+	}
+	return int(info.Window.Right-info.Window.Left) + 1, int(info.Window.Bottom-info.Window.Top) + 1, nil
+}
@@ -0,0 +1,11 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+// +build darwin freebsd netbsd openbsd dragonfly
+
+package platform
+
+import "golang.org/x/sys/unix"
+
+const (
+	ioctlGetTermios = unix.TIOCGETA // ioctlGetTermios reads the terminal attributes
+	ioctlSetTermios = unix.TIOCSETA // ioctlSetTermios writes the terminal attributes
+)
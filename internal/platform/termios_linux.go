@@ -0,0 +1,11 @@
+//go:build linux
+// +build linux
+
+package platform
+
+import "golang.org/x/sys/unix"
+
+const (
+	ioctlGetTermios = unix.TCGETS // ioctlGetTermios reads the terminal attributes
+	ioctlSetTermios = unix.TCSETS // ioctlSetTermios writes the terminal attributes
+)
@@ -0,0 +1,37 @@
+package glint
+
+import (
+	"fmt"
+
+	"github.com/droqsic/glint/internal/core"
+)
+
+type Level = core.Level // Level represents the color support capability of a terminal.
+
+const (
+	LevelNone = core.LevelNone // LevelNone indicates no color support
+	Level16   = core.Level16   // Level16 indicates basic ANSI color support (16 colors)
+	Level256  = core.Level256  // Level256 indicates extended ANSI color support (256 colors)
+	LevelTrue = core.LevelTrue // LevelTrue indicates 24-bit RGB color support (TrueColor)
+)
+
+// ParseLevel parses the short name of a level: none, 16, 256 or truecolor, with the aliases 0, ansi, ansi256, 24bit and 16m.
+func ParseLevel(s string) (Level, error) {
+	if level, ok := core.ParseLevel(s); ok {
+		return level, nil
+	}
+	return LevelNone, fmt.Errorf("invalid color level %q: must be none, 16, 256 or truecolor", s)
+}
+
+// LevelName returns the short name of a level as used in flags, environment variables and file formats.
+func LevelName(l Level) string {
+	switch l {
+	case Level16:
+		return "16"
+	case Level256:
+		return "256"
+	case LevelTrue:
+		return "truecolor"
+	}
+	return "none"
+}
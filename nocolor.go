@@ -0,0 +1,62 @@
+package glint
+
+import (
+	"io"
+	"sync/atomic"
+
+	"github.com/droqsic/glint/internal/core"
+)
+
+type NoColorPolicy int8 // NoColorPolicy selects what NO_COLOR disables.
+
+const (
+	NoColorStripAll  NoColorPolicy = iota // NoColorStripAll makes NO_COLOR disable colors and text attributes, producing plain text
+	NoColorColorOnly                      // NoColorColorOnly makes NO_COLOR disable colors only, keeping attributes such as bold and underline
+)
+
+// noColorPolicy holds the policy set with SetNoColorPolicy.
+var noColorPolicy atomic.Int32
+
+// String returns the name of the policy.
+func (p NoColorPolicy) String() string {
+	if p == NoColorColorOnly {
+		return "color-only"
+	}
+	return "strip-all"
+}
+
+// SetNoColorPolicy selects what NO_COLOR disables. The no-color.org convention asks programs to stop using color,
+// but lets them keep other styling, which NoColorColorOnly allows. The default NoColorStripAll produces plain text.
+// The policy only applies when NO_COLOR is what disables color: explicit settings such as ForceColor(false),
+// MYTOOL_COLOR=never or output that is not a terminal still disable attributes. This function is thread-safe.
+func SetNoColorPolicy(p NoColorPolicy) {
+	noColorPolicy.Store(int32(p))
+}
+
+// AttributeSupport reports whether text attributes such as bold, italic and underline may be used on stdout.
+// It is true whenever ColorSupport is, and also when NO_COLOR disabled color under NoColorColorOnly, which is the state
+// "attributes supported, colors disabled": ColorSupport is false and ColorLevel is LevelNone, but styles may keep their
+// attributes. This function is thread-safe and lock-free.
+func AttributeSupport() bool {
+	return current.Load().result().attributes()
+}
+
+// AttributeSupportFor reports whether text attributes may be used for output written to w, see AttributeSupport.
+func AttributeSupportFor(w io.Writer) bool {
+	return resultFor(w).attributes()
+}
+
+// attributes reports whether the outcome allows text attributes under the current policy.
+func (o outcome) attributes() bool {
+	return o.supported || o.noColor && NoColorPolicy(noColorPolicy.Load()) == NoColorColorOnly
+}
+
+// explanation returns the Explanation for an outcome decided by rule.
+func (o outcome) explanation(rule string) Explanation {
+	return Explanation{Supported: o.supported, Level: o.level, Attributes: o.attributes(), Rule: rule}
+}
+
+// outcomeFor returns the outcome of a level decided by rule.
+func outcomeFor(level core.Level, rule string) outcome {
+	return outcome{supported: level != core.LevelNone, level: atLeast16(level), noColor: rule == core.EnvNoColor}
+}
@@ -0,0 +1,120 @@
+package glint
+
+import "sync"
+
+var (
+	listeners      = make(map[uint64]func(old, new Level)) // listeners holds the OnChange callbacks by subscription id
+	listenerID     uint64                                  // listenerID is the id of the latest subscription
+	listenersMutex sync.Mutex                              // listenersMutex protects listeners and listenerID
+
+	pending      []transition // pending holds the transitions waiting to be delivered, in the order they happened
+	dispatching  bool         // dispatching reports whether a goroutine is delivering the pending transitions
+	pendingMutex sync.Mutex   // pendingMutex protects pending and dispatching
+)
+
+// transition is a replacement of the active snapshot.
+type transition struct {
+	old, new *snapshot
+}
+
+// OnChange registers fn to be called after ForceColor, ForceLevel, ResetColor, Refresh, SetAppName or a ColorMode
+// changes the effective ColorLevel, with the level before and after the transition. Transitions that leave the level
+// unchanged are not reported. Callbacks run after the new state is visible and may call back into glint.
+// Transitions are delivered one at a time in the order they happened, so callbacks never run concurrently and the
+// last reported level is the current one. A callback usually runs on the goroutine that made the change, but when
+// transitions overlap, or a callback changes the level itself, the goroutine already delivering reports them in turn.
+// If a callback panics, the transitions still queued are delivered before the panic propagates.
+// The returned function removes the registration and is safe to call more than once. This function is thread-safe.
+func OnChange(fn func(old, new Level)) (unsubscribe func()) {
+	listenersMutex.Lock()
+	defer listenersMutex.Unlock()
+
+	listenerID++
+	id := listenerID
+	listeners[id] = fn
+
+	return func() {
+		listenersMutex.Lock()
+		defer listenersMutex.Unlock()
+
+		delete(listeners, id)
+	}
+}
+
+// enqueue records a transition for delivery. It is called with stateMutex held, so pending keeps the order of the snapshots.
+func enqueue(old, s *snapshot) {
+	pendingMutex.Lock()
+	pending = append(pending, transition{old, s})
+	pendingMutex.Unlock()
+}
+
+// dispatch delivers the pending transitions unless another goroutine already does, which then delivers them in turn.
+func dispatch() {
+	pendingMutex.Lock()
+	if dispatching {
+		pendingMutex.Unlock()
+		return
+	}
+	dispatching = true
+	pendingMutex.Unlock()
+
+	// A panicking callback skips the listeners left for its transition, but the later transitions are still
+	// delivered before the panic propagates
+	finished := false
+	defer func() {
+		if finished {
+			return
+		}
+		pendingMutex.Lock()
+		dispatching = false
+		pendingMutex.Unlock()
+		dispatch()
+	}()
+
+	for {
+		pendingMutex.Lock()
+		if len(pending) == 0 {
+			dispatching = false
+			pendingMutex.Unlock()
+			finished = true
+			return
+		}
+		t := pending[0]
+		pending[0] = transition{}
+		pending = pending[1:]
+		pendingMutex.Unlock()
+
+		notify(t.old, t.new)
+	}
+}
+
+// hasListeners reports whether any OnChange callback is registered.
+func hasListeners() bool {
+	listenersMutex.Lock()
+	defer listenersMutex.Unlock()
+
+	return len(listeners) > 0
+}
+
+// notify calls the listeners if the level of s differs from the level of old.
+// Levels are only computed when there are listeners, so transitions stay cheap otherwise.
+func notify(old, s *snapshot) {
+	listenersMutex.Lock()
+	if len(listeners) == 0 {
+		listenersMutex.Unlock()
+		return
+	}
+	fns := make([]func(old, new Level), 0, len(listeners))
+	for _, fn := range listeners {
+		fns = append(fns, fn)
+	}
+	listenersMutex.Unlock()
+
+	oldLevel, newLevel := old.result().level, s.result().level
+	if oldLevel == newLevel {
+		return
+	}
+	for _, fn := range fns {
+		fn(oldLevel, newLevel)
+	}
+}
@@ -0,0 +1,80 @@
+package glint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/droqsic/glint/internal/core"
+)
+
+// Palette describes how a terminal displays its default colors and the 16 basic ANSI colors.
+// Colors are CSS color strings such as "#cd0000". It is used when rendering terminal output in other formats.
+type Palette struct {
+	Foreground string     // Foreground is the default text color
+	Background string     // Background is the default background color
+	Colors     [16]string // Colors holds the basic colors: black, red, green, yellow, blue, magenta, cyan, white, then their bright variants
+}
+
+// PaletteXterm is the default xterm color scheme.
+var PaletteXterm = Palette{
+	Foreground: "#e5e5e5",
+	Background: "#000000",
+	Colors: [16]string{
+		"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+		"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+	},
+}
+
+// PaletteSolarized is the dark variant of Ethan Schoonover's Solarized color scheme.
+var PaletteSolarized = Palette{
+	Foreground: "#839496",
+	Background: "#002b36",
+	Colors: [16]string{
+		"#073642", "#dc322f", "#859900", "#b58900", "#268bd2", "#d33682", "#2aa198", "#eee8d5",
+		"#002b36", "#cb4b16", "#586e75", "#657b83", "#839496", "#6c71c4", "#93a1a1", "#fdf6e3",
+	},
+}
+
+// PaletteDracula is the Dracula color scheme.
+var PaletteDracula = Palette{
+	Foreground: "#f8f8f2",
+	Background: "#282a36",
+	Colors: [16]string{
+		"#21222c", "#ff5555", "#50fa7b", "#f1fa8c", "#bd93f9", "#ff79c6", "#8be9fd", "#f8f8f2",
+		"#6272a4", "#ff6e6e", "#69ff94", "#ffffa5", "#d6acff", "#ff92df", "#a4ffff", "#ffffff",
+	},
+}
+
+// PaletteNamed returns the built-in palette with the given name: "xterm", "solarized" or "dracula".
+func PaletteNamed(name string) (Palette, bool) {
+	switch strings.ToLower(name) {
+	case "xterm":
+		return PaletteXterm, true
+	case "solarized":
+		return PaletteSolarized, true
+	case "dracula":
+		return PaletteDracula, true
+	}
+	return Palette{}, false
+}
+
+// cssColor returns the CSS color for c using the palette for the basic colors.
+// It returns an empty string for the default color.
+func (p Palette) cssColor(c core.Color) string {
+	if c.IsDefault() {
+		return ""
+	}
+	if i, ok := c.BasicIndex(); ok {
+		return p.Colors[i]
+	}
+	r, g, b := c.RGB()
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// orDefault returns p, or PaletteXterm if p is the zero value.
+func (p Palette) orDefault() Palette {
+	if p == (Palette{}) {
+		return PaletteXterm
+	}
+	return p
+}
@@ -0,0 +1,57 @@
+// Package pty runs child processes under a pseudo-terminal, so programs that only emit color when their output is a
+// terminal behave as they would interactively. Pseudo-terminals are supported on Linux. On other platforms Start
+// returns ErrUnsupported.
+package pty
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+)
+
+// ErrUnsupported is returned where pseudo-terminals are not supported.
+var ErrUnsupported = errors.New("pseudo-terminals are not supported on this platform")
+
+// Size is the size of a terminal in cells.
+type Size struct {
+	Columns int // Columns is the number of columns
+	Rows    int // Rows is the number of rows
+}
+
+// Options configures a pseudo-terminal started with StartWith.
+type Options struct {
+	Size      Size // Size is the initial size, the zero value leaves the kernel default
+	RawOutput bool // RawOutput turns off output processing before the child starts, see Terminal.RawOutput
+}
+
+// Terminal is the controlling side of a pseudo-terminal. Reading from it returns what the child writes,
+// and writing to it is seen by the child as keyboard input.
+type Terminal struct {
+	f *os.File
+}
+
+// Read reads the child's output. The end of the output, which Linux reports as an I/O error once every process
+// holding the terminal has exited, is returned as io.EOF.
+func (t *Terminal) Read(p []byte) (int, error) {
+	n, err := t.f.Read(p)
+	if errors.Is(err, syscall.EIO) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Write sends input to the child.
+func (t *Terminal) Write(p []byte) (int, error) {
+	return t.f.Write(p)
+}
+
+// Close closes the controlling side, which hangs up the child's terminal.
+func (t *Terminal) Close() error {
+	return t.f.Close()
+}
+
+// Fd returns the file descriptor of the controlling side.
+func (t *Terminal) Fd() uintptr {
+	return t.f.Fd()
+}
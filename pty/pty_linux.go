@@ -0,0 +1,169 @@
+//go:build linux
+// +build linux
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Start runs cmd with its standard input, output and error connected to a new pseudo-terminal of the given size,
+// replacing any that were set, and makes the terminal the controlling terminal of a new session.
+// A zero size leaves the kernel default. The caller reads the child's output from the returned Terminal,
+// calls cmd.Wait once the output ends, and closes the Terminal.
+func Start(cmd *exec.Cmd, size Size) (*Terminal, error) {
+	return StartWith(cmd, Options{Size: size})
+}
+
+// StartWith runs cmd like Start, with the terminal configured by opts before the child starts.
+func StartWith(cmd *exec.Cmd, opts Options) (*Terminal, error) {
+	master, slave, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer slave.Close()
+
+	t := &Terminal{f: master}
+	if opts.Size != (Size{}) {
+		if err := t.Resize(opts.Size); err != nil {
+			master.Close()
+			return nil, err
+		}
+	}
+	if opts.RawOutput {
+		if err := t.RawOutput(); err != nil {
+			master.Close()
+			return nil, err
+		}
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	attr := syscall.SysProcAttr{}
+	if cmd.SysProcAttr != nil {
+		attr = *cmd.SysProcAttr
+	}
+	attr.Setsid, attr.Setctty, attr.Ctty = true, true, 0
+	cmd.SysProcAttr = &attr
+
+	if err := cmd.Start(); err != nil {
+		master.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// open allocates a pseudo-terminal pair from /dev/ptmx.
+func open() (master, slave *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	n, err := unix.IoctlGetUint32(fd, unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+
+	slave, err = os.OpenFile("/dev/pts/"+strconv.Itoa(int(n)), os.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
+
+// Resize changes the size of the terminal. The child receives SIGWINCH.
+func (t *Terminal) Resize(size Size) error {
+	ws := &unix.Winsize{Col: uint16(size.Columns), Row: uint16(size.Rows)}
+	return unix.IoctlSetWinsize(int(t.f.Fd()), unix.TIOCSWINSZ, ws)
+}
+
+// Size returns the size of the terminal.
+func (t *Terminal) Size() (Size, error) {
+	ws, err := unix.IoctlGetWinsize(int(t.f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return Size{}, err
+	}
+	return Size{Columns: int(ws.Col), Rows: int(ws.Row)}, nil
+}
+
+// RawOutput turns off output processing, so newlines the child writes are not expanded to CR LF.
+// This suits output that is captured to a file rather than shown on a terminal. Output the child wrote before
+// the call has already been processed, use Options.RawOutput to turn processing off before the child starts.
+func (t *Terminal) RawOutput() error {
+	fd := int(t.f.Fd())
+	termios, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return err
+	}
+	termios.Oflag &^= unix.OPOST
+	return unix.IoctlSetTermios(fd, unix.TCSETS, termios)
+}
+
+// TrackSize copies the size of the terminal open on f to t now and whenever this process receives SIGWINCH,
+// until stop is called.
+func (t *Terminal) TrackSize(f *os.File) (stop func()) {
+	resize := func() {
+		if ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ); err == nil {
+			t.Resize(Size{Columns: int(ws.Col), Rows: int(ws.Row)})
+		}
+	}
+	resize()
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, unix.SIGWINCH)
+	go func() {
+		for {
+			select {
+			case <-signals:
+				resize()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// MakeRaw puts the terminal open on f in raw mode, so keystrokes reach the child unprocessed,
+// and returns a function restoring the previous mode.
+func MakeRaw(f *os.File) (restore func() error, err error) {
+	fd := int(f.Fd())
+	saved, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *saved
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, unix.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return unix.IoctlSetTermios(fd, unix.TCSETS, saved)
+	}, nil
+}
@@ -0,0 +1,44 @@
+//go:build !linux
+// +build !linux
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Start is not supported on this platform and always returns ErrUnsupported.
+func Start(cmd *exec.Cmd, size Size) (*Terminal, error) {
+	return nil, ErrUnsupported
+}
+
+// StartWith is not supported on this platform and always returns ErrUnsupported.
+func StartWith(cmd *exec.Cmd, opts Options) (*Terminal, error) {
+	return nil, ErrUnsupported
+}
+
+// Resize is not supported on this platform and always returns ErrUnsupported.
+func (t *Terminal) Resize(size Size) error {
+	return ErrUnsupported
+}
+
+// Size is not supported on this platform and always returns ErrUnsupported.
+func (t *Terminal) Size() (Size, error) {
+	return Size{}, ErrUnsupported
+}
+
+// RawOutput is not supported on this platform and always returns ErrUnsupported.
+func (t *Terminal) RawOutput() error {
+	return ErrUnsupported
+}
+
+// TrackSize is not supported on this platform and does nothing.
+func (t *Terminal) TrackSize(f *os.File) (stop func()) {
+	return func() {}
+}
+
+// MakeRaw is not supported on this platform and always returns ErrUnsupported.
+func MakeRaw(f *os.File) (restore func() error, err error) {
+	return nil, ErrUnsupported
+}
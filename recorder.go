@@ -0,0 +1,132 @@
+package glint
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/droqsic/glint/internal/ansi"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// Recorder is an io.Writer that records everything written to it as an asciinema asciicast v2 file.
+// Each Write becomes an output event stamped with the time elapsed since the recording started.
+// Combine it with io.MultiWriter to show output while recording it. A Recorder is safe for concurrent use.
+type Recorder struct {
+	w       io.Writer
+	width   int
+	height  int
+	mu      sync.Mutex
+	clock   func() time.Time // clock returns the current time, time.Now unless replaced with SetClock
+	start   time.Time        // start is the time the header was written
+	started bool             // started is set once the header has been written
+	partial string           // partial holds the bytes of a character split across writes
+	err     error            // err is the first error returned by w, reported by every later call
+}
+
+// asciicastHeader is the first line of an asciicast v2 file.
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env"`
+}
+
+// NewRecorder returns a Recorder writing an asciicast for a terminal of width columns and height rows to w.
+// The header is written on the first Write or Close and records TERM and the effective ColorLevel,
+// so players can tell which colors the recorded program chose to emit.
+func NewRecorder(w io.Writer, width, height int) *Recorder {
+	return &Recorder{w: w, width: width, height: height, clock: time.Now}
+}
+
+// SetClock replaces the time source used for the header timestamp and event times.
+// It is meant for deterministic tests and must be called before the first Write.
+func (r *Recorder) SetClock(now func() time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = now
+}
+
+// Write records p as an output event. A UTF-8 character split across calls is held back until it is complete.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := r.partial + string(p)
+	r.partial = ""
+	if cut := ansi.PartialRune(data); cut > 0 {
+		data, r.partial = data[:len(data)-cut], data[len(data)-cut:]
+	}
+
+	if data != "" {
+		r.event("o", data)
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	return len(p), nil
+}
+
+// Resize records a change of the terminal size.
+func (r *Recorder) Resize(width, height int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.event("r", strconv.Itoa(width)+"x"+strconv.Itoa(height))
+	return r.err
+}
+
+// Close writes any held back bytes and the header if nothing was recorded. It does not close the underlying writer.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.partial != "" {
+		r.event("o", r.partial)
+		r.partial = ""
+	}
+	r.header()
+	return r.err
+}
+
+// header writes the asciicast header if it hasn't been written yet.
+func (r *Recorder) header() {
+	if r.started || r.err != nil {
+		return
+	}
+	r.started = true
+	r.start = r.clock()
+
+	line, err := json.Marshal(asciicastHeader{
+		Version:   2,
+		Width:     r.width,
+		Height:    r.height,
+		Timestamp: r.start.Unix(),
+		Env: map[string]string{
+			"TERM":              core.GetEnvCache(core.EnvTerm),
+			"GLINT_COLOR_LEVEL": LevelName(ColorLevel()),
+		},
+	})
+	if err == nil {
+		_, err = r.w.Write(append(line, '\n'))
+	}
+	r.err = err
+}
+
+// event writes an event of the given type, writing the header first if needed.
+func (r *Recorder) event(kind, data string) {
+	r.header()
+	if r.err != nil {
+		return
+	}
+
+	elapsed := r.clock().Sub(r.start).Seconds()
+	line, err := json.Marshal([]any{json.Number(strconv.FormatFloat(elapsed, 'f', 6, 64)), kind, data})
+	if err == nil {
+		_, err = r.w.Write(append(line, '\n'))
+	}
+	r.err = err
+}
@@ -0,0 +1,28 @@
+package glint
+
+import (
+	"errors"
+	"os"
+
+	"github.com/droqsic/glint/internal/core"
+	"github.com/droqsic/glint/internal/platform"
+)
+
+// errNoTerminal is returned by TerminalSize when stdout is not a terminal.
+var errNoTerminal = errors.New("stdout is not a terminal")
+
+// TerminalSize returns the width and height in cells of the terminal attached to stdout.
+// It returns an error if stdout is not a terminal or its size can't be queried on this platform.
+func TerminalSize() (columns, rows int, err error) {
+	if f := core.ActiveFake(); f != nil {
+		if !f.TTY {
+			return 0, 0, errNoTerminal
+		}
+		return f.Columns, f.Rows, nil
+	}
+
+	if !isTerminal(os.Stdout) {
+		return 0, 0, errNoTerminal
+	}
+	return platform.TerminalSize(os.Stdout.Fd())
+}
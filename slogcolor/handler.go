@@ -0,0 +1,382 @@
+// Package slogcolor provides a slog.Handler that writes human-readable, colored log records.
+// Colors are enabled only when glint reports that the destination can render them, so the same
+// handler produces plain text when output is redirected to a file or a pipe.
+package slogcolor
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/droqsic/glint"
+)
+
+// DefaultTimeFormat is the layout used for record times when Options.TimeFormat is empty.
+const DefaultTimeFormat = "15:04:05.000"
+
+// Options configures a Handler. The fields mirror slog.HandlerOptions.
+type Options struct {
+	AddSource   bool                                         // AddSource adds the source file and line of the log call
+	Level       slog.Leveler                                 // Level is the minimum level to log, slog.LevelInfo if nil
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr // ReplaceAttr rewrites or drops attributes as in slog.HandlerOptions
+	TimeFormat  string                                       // TimeFormat is the layout for record times, DefaultTimeFormat if empty
+}
+
+// Handler is a slog.Handler that writes records as a single line of the form
+//
+//	15:04:05.000 INF message key=value group.key=value
+//
+// The time is dimmed, the level is shown as a colored badge, keys share one hue, values are colored by type and errors are red.
+// Grouping, WithAttrs and ReplaceAttr behave as they do for slog.TextHandler, except that the built-in time, level and
+// message attributes are written without their keys. A level set on the context of a record with glint.WithLevel
+// replaces the level of the destination for that record. A Handler is safe for concurrent use.
+type Handler struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	opts   Options
+	colors palette
+	attrs  []byte   // attrs holds the attributes added with WithAttrs, already formatted
+	rich   []byte   // rich holds the same attributes formatted with richColors, to render them at a context level
+	groups []string // groups holds the groups opened with WithGroup
+	prefix string   // prefix qualifies the keys of new attributes with the open groups
+}
+
+// palette holds the escape sequences used for each part of a record, all empty when color is disabled.
+// Strings and other values without a dedicated color keep the default color.
+type palette struct {
+	time, key, number, boolean, err, source, reset string
+	levels                                         [4]string   // levels holds the badge colors for debug, info, warn and error
+	styled                                         bool        // styled is set if the palette uses escape sequences at all
+	level                                          glint.Level // level is the color level the palette was built for
+}
+
+// richColors is the palette attributes added with WithAttrs are also formatted with, so they can be rendered at the
+// level of a context.
+var richColors = newPalette(glint.LevelTrue)
+
+// contextColors holds the palettes for the levels set with glint.WithLevel. LevelNone produces plain text.
+var contextColors = [...]palette{{}, newPalette(glint.Level16), newPalette(glint.Level256), richColors}
+
+// NewHandler returns a Handler writing to w. Color is used if glint.ColorSupportFor(w) reports that w supports it,
+// and colors are downsampled to glint.ColorLevelFor(w). Under glint.NoColorColorOnly, NO_COLOR keeps the bold and
+// dimmed parts without their colors. A nil opts is the same as the zero Options.
+func NewHandler(w io.Writer, opts *Options) *Handler {
+	h := &Handler{w: w, mu: &sync.Mutex{}}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.TimeFormat == "" {
+		h.opts.TimeFormat = DefaultTimeFormat
+	}
+	if glint.AttributeSupportFor(w) {
+		h.colors = newPalette(glint.ColorLevelFor(w))
+	}
+	return h
+}
+
+// newPalette builds the escape sequences for a color level. At LevelNone only the attributes remain.
+func newPalette(level glint.Level) palette {
+	fg := func(c glint.Color) string {
+		return c.Resolve(level).Sequence(false)
+	}
+	badge := func(c glint.Color) string {
+		return "\x1b[1m" + fg(c)
+	}
+	return palette{
+		time:    "\x1b[2m",
+		key:     fg(glint.ANSI(6)),
+		number:  fg(glint.ANSI(5)),
+		boolean: fg(glint.ANSI(3)),
+		err:     fg(glint.ANSI(9)),
+		source:  "\x1b[2m",
+		reset:   glint.Reset,
+		levels:  [4]string{badge(glint.ANSI(4)), badge(glint.ANSI(2)), badge(glint.ANSI(3)), badge(glint.ANSI(1))},
+		styled:  true,
+		level:   level,
+	}
+}
+
+// render converts attributes formatted with richColors to the escape sequences of p, a palette from contextColors.
+func (p palette) render(rich []byte) []byte {
+	if !p.styled {
+		return []byte(glint.Strip(string(rich)))
+	}
+	return []byte(glint.Downsample(string(rich), p.level))
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// WithAttrs returns a handler that adds attrs to every record, qualified by the groups opened so far.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := h.clone()
+	own, rich := *h2, *h2
+	rich.colors = richColors
+
+	// ReplaceAttr runs once per attribute, the colored copy replays its results
+	if replace := h2.opts.ReplaceAttr; replace != nil {
+		var replaced []slog.Attr
+		own.opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			a = replace(groups, a)
+			replaced = append(replaced, a)
+			return a
+		}
+		rich.opts.ReplaceAttr = func([]string, slog.Attr) slog.Attr {
+			a := replaced[0]
+			replaced = replaced[1:]
+			return a
+		}
+	}
+
+	for _, a := range attrs {
+		h2.attrs = own.appendAttr(h2.attrs, a, h2.prefix, h2.groups)
+		h2.rich = rich.appendAttr(h2.rich, a, h2.prefix, h2.groups)
+	}
+	return h2
+}
+
+// WithGroup returns a handler that qualifies the keys of later attributes with name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := h.clone()
+	h2.groups = append(h2.groups, name)
+	h2.prefix += name + "."
+	return h2
+}
+
+// clone returns a copy of h that shares its writer and lock.
+func (h *Handler) clone() *Handler {
+	h2 := *h
+	h2.attrs = slices.Clip(h.attrs)
+	h2.rich = slices.Clip(h.rich)
+	h2.groups = slices.Clip(h.groups)
+	return &h2
+}
+
+// Handle formats r and writes it as one line, at the level of ctx if one was set with glint.WithLevel.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if level, ok := glint.ContextLevel(ctx); ok {
+		h2 := *h
+		h2.colors = contextColors[min(level, glint.LevelTrue)]
+		h2.attrs = h2.colors.render(h.rich)
+		h = &h2
+	}
+
+	buf := make([]byte, 0, 256)
+
+	if !r.Time.IsZero() {
+		buf = h.appendBuiltin(buf, slog.Time(slog.TimeKey, r.Time), h.colors.time)
+	}
+	buf = h.appendBuiltin(buf, slog.Any(slog.LevelKey, r.Level), "")
+	buf = h.appendBuiltin(buf, slog.String(slog.MessageKey, r.Message), "")
+
+	if h.opts.AddSource && r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		frame, _ := frames.Next()
+		source := &slog.Source{Function: frame.Function, File: frame.File, Line: frame.Line}
+		buf = h.appendAttr(buf, slog.Any(slog.SourceKey, source), "", nil)
+	}
+
+	buf = append(buf, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendAttr(buf, a, h.prefix, h.groups)
+		return true
+	})
+
+	if len(buf) > 0 && buf[0] == ' ' {
+		buf = buf[1:]
+	}
+	buf = append(buf, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf)
+	return err
+}
+
+// appendBuiltin appends the time, level or message of a record without its key, after passing it through ReplaceAttr.
+func (h *Handler) appendBuiltin(buf []byte, a slog.Attr, color string) []byte {
+	if h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(nil, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Key == "" {
+		return buf
+	}
+
+	buf = append(buf, ' ')
+	switch v := a.Value; {
+	case v.Kind() == slog.KindTime:
+		return h.paint(buf, color, v.Time().Format(h.opts.TimeFormat))
+	case isLevel(v):
+		level := v.Any().(slog.Level)
+		return h.paint(buf, h.levelColor(level), levelBadge(level))
+	default:
+		return append(buf, quoteIfControl(v.String())...)
+	}
+}
+
+// appendAttr appends a key=value pair after passing it through ReplaceAttr. Groups are flattened into dotted keys.
+func (h *Handler) appendAttr(buf []byte, a slog.Attr, prefix string, groups []string) []byte {
+	a.Value = a.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return buf
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return buf
+		}
+		if a.Key != "" {
+			prefix += a.Key + "."
+			groups = append(slices.Clip(groups), a.Key)
+		}
+		for _, ga := range attrs {
+			buf = h.appendAttr(buf, ga, prefix, groups)
+		}
+		return buf
+	}
+
+	buf = append(buf, ' ')
+	buf = h.paint(buf, h.colors.key, quoteIfNeeded(prefix+a.Key))
+	buf = append(buf, '=')
+	return h.appendValue(buf, a.Value)
+}
+
+// appendValue appends a value, colored by its type.
+func (h *Handler) appendValue(buf []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindDuration:
+		return h.paint(buf, h.colors.number, v.String())
+	case slog.KindBool:
+		return h.paint(buf, h.colors.boolean, v.String())
+	case slog.KindTime:
+		return append(buf, quoteIfNeeded(v.Time().Format(time.RFC3339Nano))...)
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case error:
+			return h.paint(buf, h.colors.err, quoteIfNeeded(x.Error()))
+		case *slog.Source:
+			return h.paint(buf, h.colors.source, quoteIfNeeded(x.File+":"+strconv.Itoa(x.Line)))
+		case encoding.TextMarshaler:
+			if text, err := x.MarshalText(); err == nil {
+				return append(buf, quoteIfNeeded(string(text))...)
+			}
+		case []byte:
+			return append(buf, quoteIfNeeded(string(x))...)
+		}
+		return append(buf, quoteIfNeeded(fmt.Sprint(v.Any()))...)
+	default:
+		return append(buf, quoteIfNeeded(v.String())...)
+	}
+}
+
+// paint appends s wrapped in color and a reset, or s alone if color is empty.
+func (h *Handler) paint(buf []byte, color, s string) []byte {
+	if color == "" {
+		return append(buf, s...)
+	}
+	buf = append(buf, color...)
+	buf = append(buf, s...)
+	return append(buf, h.colors.reset...)
+}
+
+// levelColor returns the badge color for a level.
+func (h *Handler) levelColor(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return h.colors.levels[0]
+	case level < slog.LevelWarn:
+		return h.colors.levels[1]
+	case level < slog.LevelError:
+		return h.colors.levels[2]
+	default:
+		return h.colors.levels[3]
+	}
+}
+
+// levelBadge returns the three letter abbreviation of a level, with the offset from the base level if any.
+func levelBadge(level slog.Level) string {
+	name, base := "ERR", slog.LevelError
+	switch {
+	case level < slog.LevelInfo:
+		name, base = "DBG", slog.LevelDebug
+	case level < slog.LevelWarn:
+		name, base = "INF", slog.LevelInfo
+	case level < slog.LevelError:
+		name, base = "WRN", slog.LevelWarn
+	}
+	if level != base {
+		return fmt.Sprintf("%s%+d", name, level-base)
+	}
+	return name
+}
+
+// isLevel reports whether v holds a slog.Level.
+func isLevel(v slog.Value) bool {
+	if v.Kind() != slog.KindAny {
+		return false
+	}
+	_, ok := v.Any().(slog.Level)
+	return ok
+}
+
+// quoteIfControl quotes s with Go syntax if it contains control characters or invalid UTF-8, so a message can neither
+// break the one-line format nor send escape sequences to the terminal. Other messages are kept readable as they are.
+func quoteIfControl(s string) string {
+	for _, r := range s {
+		if r == utf8.RuneError || unicode.IsControl(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// quoteIfNeeded quotes s with Go syntax if it is empty or contains spaces, quotes, equal signs or unprintable characters,
+// following the rules of slog.TextHandler.
+func quoteIfNeeded(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			if b <= ' ' || b == '=' || b == '"' || b == 0x7f {
+				return strconv.Quote(s)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+		i += size
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+package glint
+
+import "strings"
+
+// Style is a foreground color with text attributes, used for one semantic part of the output such as errors or headings.
+// The zero value leaves text unchanged.
+type Style struct {
+	Color     AdaptiveColor // Color is the foreground color, the terminal's default if both variants are the default
+	Bold      bool          // Bold renders text with increased intensity
+	Dim       bool          // Dim renders text with decreased intensity
+	Italic    bool          // Italic renders text in italics
+	Underline bool          // Underline underlines text
+}
+
+// Sequence returns the escape sequence applying the style on a terminal with the given level, with the color variant
+// matching the terminal background downsampled to level. At LevelNone only the attributes remain.
+// It returns an empty string if the style changes nothing.
+func (s Style) Sequence(level Level) string {
+	var params []string
+	for _, attr := range [...]struct {
+		on    bool
+		param string
+	}{{s.Bold, "1"}, {s.Dim, "2"}, {s.Italic, "3"}, {s.Underline, "4"}} {
+		if attr.on {
+			params = append(params, attr.param)
+		}
+	}
+	// Checking for a color first avoids detecting the background for styles without one
+	if level != LevelNone && (!s.Color.Light.IsDefault() || !s.Color.Dark.IsDefault()) {
+		if c := s.Color.Resolve(level); !c.IsDefault() {
+			params = append(params, c.value.Params(false))
+		}
+	}
+
+	if len(params) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// RenderLevel wraps text in the style for a terminal with the given level, such as one from LevelFromContext,
+// followed by Reset. See Sequence.
+func (s Style) RenderLevel(text string, level Level) string {
+	seq := s.Sequence(level)
+	if seq == "" {
+		return text
+	}
+	return seq + text + Reset
+}
+
+// Render wraps text in the style for stdout. Colors are downsampled to ColorLevel(), and text is returned unchanged
+// if AttributeSupport reports that stdout shows no styling, so under NoColorColorOnly only the attributes remain.
+func (s Style) Render(text string) string {
+	if !AttributeSupport() {
+		return text
+	}
+	return s.RenderLevel(text, ColorLevel())
+}
@@ -0,0 +1,257 @@
+package glint
+
+import (
+	"bufio"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/droqsic/glint/internal/ansi"
+)
+
+const (
+	svgTabWidth   = 8   // svgTabWidth is the distance between tab stops in columns
+	svgPadding    = 16  // svgPadding is the space between the window edge and the text grid
+	svgTitleBar   = 32  // svgTitleBar is the height of the window title bar
+	svgCharAspect = 0.6 // svgCharAspect is the width of a monospace cell relative to the font size
+	svgLineHeight = 1.4 // svgLineHeight is the height of a row relative to the font size
+)
+
+// SVGOptions controls how ToSVG renders terminal output.
+type SVGOptions struct {
+	Level      Level   // Level is the color level to emulate and colors are downsampled to it, LevelTrue if zero
+	Monochrome bool    // Monochrome renders no colors, like a terminal at LevelNone, while keeping attributes
+	Palette    Palette // Palette supplies the default and basic colors, PaletteXterm if zero
+	Columns    int     // Columns wraps lines at this many cells like a terminal would, 0 fits the longest line
+	FontSize   float64 // FontSize is the font size in pixels, 14 if zero
+	FontFamily string  // FontFamily is the CSS font stack, a common monospace stack if empty
+	Title      string  // Title is shown in the window title bar
+	NoWindow   bool    // NoWindow omits the window frame and title bar
+}
+
+// svgCell is one column of the rendered grid.
+type svgCell struct {
+	text  string     // text is the grapheme drawn in the column, empty for the second column of a wide character
+	state ansi.State // state is the rendition of the column
+}
+
+// ToSVG renders ANSI-colored text read from r as an SVG image of a terminal window written to w.
+// Text is laid out on a monospace grid and colors are shown as a terminal at opts.Level would show them, in full like
+// ToHTML if no level is set, with the basic colors taken from opts.Palette. Cursor movement and other escape sequences
+// are ignored.
+func ToSVG(r io.Reader, w io.Writer, opts SVGOptions) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	opts.Palette = opts.Palette.orDefault()
+	if opts.FontSize <= 0 {
+		opts.FontSize = 14
+	}
+	if opts.FontFamily == "" {
+		opts.FontFamily = "ui-monospace, SFMono-Regular, Menlo, Consolas, 'DejaVu Sans Mono', monospace"
+	}
+
+	level := opts.Level
+	if opts.Monochrome {
+		level = LevelNone
+	} else if level == LevelNone {
+		level = LevelTrue
+	}
+
+	lines := svgGrid(string(data), opts.Columns, level)
+	columns := opts.Columns
+	if columns <= 0 {
+		for _, line := range lines {
+			columns = max(columns, len(line))
+		}
+	}
+
+	cellWidth := opts.FontSize * svgCharAspect
+	lineHeight := opts.FontSize * svgLineHeight
+	top := float64(svgPadding)
+	width := float64(columns)*cellWidth + 2*svgPadding
+	if !opts.NoWindow {
+		top += svgTitleBar
+		width = max(width, 96)
+	}
+	height := top + float64(len(lines))*lineHeight + svgPadding
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + svgNum(width) + `" height="` + svgNum(height) +
+		`" viewBox="0 0 ` + svgNum(width) + ` ` + svgNum(height) + `" font-family="` + html.EscapeString(opts.FontFamily) +
+		`" font-size="` + svgNum(opts.FontSize) + `" xml:space="preserve">` + "\n")
+
+	if opts.NoWindow {
+		bw.WriteString(`<rect width="100%" height="100%" fill="` + html.EscapeString(opts.Palette.Background) + `"/>` + "\n")
+	} else {
+		bw.WriteString(`<rect width="100%" height="100%" rx="8" fill="` + html.EscapeString(opts.Palette.Background) + `"/>` + "\n")
+		for i, color := range [...]string{"#ff5f56", "#ffbd2e", "#27c93f"} {
+			bw.WriteString(`<circle cx="` + strconv.Itoa(20+i*20) + `" cy="16" r="6" fill="` + color + `"/>` + "\n")
+		}
+		if opts.Title != "" {
+			bw.WriteString(`<text x="` + svgNum(width/2) + `" y="21" text-anchor="middle" fill="` +
+				html.EscapeString(opts.Palette.Foreground) + `" opacity="0.7">` + html.EscapeString(opts.Title) + "</text>\n")
+		}
+	}
+
+	for row, line := range lines {
+		y := top + float64(row)*lineHeight
+		for start := 0; start < len(line); {
+			end := start + 1
+			for end < len(line) && line[end].state == line[start].state {
+				end++
+			}
+			svgRun(bw, line[start:end], opts.Palette, svgPadding+float64(start)*cellWidth, y, cellWidth, lineHeight)
+			start = end
+		}
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// svgGrid lays out s on a grid of columns, wrapping at columns cells if it is positive,
+// and downsamples every color to level.
+func svgGrid(s string, columns int, level Level) [][]svgCell {
+	cells, _ := ansi.Cells(s)
+	lines := [][]svgCell{nil}
+	var state ansi.State
+	col := 0
+
+	put := func(cell svgCell, width int) {
+		if columns > 0 && col+width > columns {
+			lines = append(lines, nil)
+			col = 0
+		}
+		line := lines[len(lines)-1]
+		for len(line) < col+width {
+			line = append(line, svgCell{text: " "})
+		}
+		line[col] = cell
+		for i := 1; i < width; i++ {
+			line[col+i] = svgCell{state: cell.state}
+		}
+		lines[len(lines)-1] = line
+		col += width
+	}
+
+	for _, cell := range cells {
+		for _, tok := range cell.Prefix {
+			state.Apply(tok)
+		}
+		rendered := state
+		rendered.Fg, rendered.Bg = state.Fg.Convert(level), state.Bg.Convert(level)
+		rendered.LinkParams = ""
+
+		switch cell.Text {
+		case "\n":
+			lines = append(lines, nil)
+			col = 0
+		case "\r":
+			col = 0
+		case "\t":
+			for next := (col/svgTabWidth + 1) * svgTabWidth; col < next && (columns <= 0 || col < columns); {
+				put(svgCell{text: " ", state: rendered}, 1)
+			}
+		default:
+			if cell.Width > 0 {
+				put(svgCell{text: cell.Text, state: rendered}, cell.Width)
+			}
+		}
+	}
+
+	// Trailing blank cells in the default style are not drawn and do not count toward the width.
+	for i, line := range lines {
+		end := len(line)
+		for end > 0 && line[end-1].text == " " && !line[end-1].state.Styled() && line[end-1].state.Link == "" {
+			end--
+		}
+		lines[i] = line[:end]
+	}
+	return lines
+}
+
+// svgRun draws a run of cells sharing one rendition: its background, its text and its hyperlink.
+func svgRun(w *bufio.Writer, run []svgCell, palette Palette, x, y, cellWidth, lineHeight float64) {
+	state := run[0].state
+	width := float64(len(run)) * cellWidth
+
+	fg, bg := palette.cssColor(state.Fg), palette.cssColor(state.Bg)
+	if state.Attrs&ansi.AttrReverse != 0 {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = palette.Background
+		}
+		if bg == "" {
+			bg = palette.Foreground
+		}
+	}
+	if bg != "" {
+		w.WriteString(`<rect x="` + svgNum(x) + `" y="` + svgNum(y) + `" width="` + svgNum(width) + `" height="` +
+			svgNum(lineHeight) + `" fill="` + html.EscapeString(bg) + `"/>` + "\n")
+	}
+
+	var text strings.Builder
+	for _, cell := range run {
+		text.WriteString(cell.text)
+	}
+	if state.Attrs&ansi.AttrHidden != 0 || strings.TrimSpace(text.String()) == "" {
+		return
+	}
+
+	link := safeLink(state.Link)
+	if link {
+		w.WriteString(`<a href="` + html.EscapeString(state.Link) + `">`)
+	}
+
+	if fg == "" {
+		fg = palette.Foreground
+	}
+	w.WriteString(`<text x="` + svgNum(x) + `" y="` + svgNum(y+lineHeight*0.75) + `" textLength="` + svgNum(width) +
+		`" lengthAdjust="spacingAndGlyphs" fill="` + html.EscapeString(fg) + `"`)
+	if state.Attrs&ansi.AttrBold != 0 {
+		w.WriteString(` font-weight="bold"`)
+	}
+	if state.Attrs&ansi.AttrItalic != 0 {
+		w.WriteString(` font-style="italic"`)
+	}
+	if state.Attrs&ansi.AttrDim != 0 {
+		w.WriteString(` opacity="0.6"`)
+	}
+	var decorations []string
+	for _, d := range [...]struct {
+		attr ansi.Attr
+		name string
+	}{{ansi.AttrUnderline, "underline"}, {ansi.AttrStrike, "line-through"}, {ansi.AttrOverline, "overline"}} {
+		if state.Attrs&d.attr != 0 {
+			decorations = append(decorations, d.name)
+		}
+	}
+	if len(decorations) > 0 {
+		w.WriteString(` text-decoration="` + strings.Join(decorations, " ") + `"`)
+	}
+	w.WriteString(">" + html.EscapeString(svgText(text.String())) + "</text>")
+
+	if link {
+		w.WriteString("</a>")
+	}
+	w.WriteString("\n")
+}
+
+// svgText removes characters that are not allowed in XML documents.
+func svgText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == 0xfffe || r == 0xffff {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// svgNum formats a coordinate with at most two decimals.
+func svgNum(v float64) string {
+	return strconv.FormatFloat(float64(int(v*100+0.5))/100, 'f', -1, 64)
+}
@@ -0,0 +1,158 @@
+package fuzz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/glinttest"
+	"github.com/droqsic/glint/internal/ansi"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// FuzzNext checks that the tokenizer splits any input into tokens that cover it
+func FuzzNext(f *testing.F) {
+	for _, s := range []string{"plain", "\x1b[1;31mred\x1b[0m", "\x1b]8;;https://x\x07a\x1b]8;;\x1b\\", "\x1bP>|xterm\x1b\\", "\x1b[", "\x1b]11;\x1b[c", "\x1b(B"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		rest := s
+		for {
+			tok, ok := ansi.Next(rest)
+			if !ok {
+				break
+			}
+			if tok.Raw == "" || !strings.HasPrefix(rest, tok.Raw) {
+				t.Fatalf("token %q is not a non-empty prefix of %q", tok.Raw, rest)
+			}
+			if tok.Kind == ansi.TokenText && strings.IndexByte(tok.Raw, ansi.ESC) >= 0 {
+				t.Fatalf("text token %q contains an escape", tok.Raw)
+			}
+			rest = rest[len(tok.Raw):]
+		}
+		if rest != "" && rest[0] != ansi.ESC {
+			t.Fatalf("only an unterminated escape sequence may be left over, got %q", rest)
+		}
+	})
+}
+
+// FuzzFilters checks that the filters agree with each other whole and streamed one byte at a time
+func FuzzFilters(f *testing.F) {
+	for _, s := range []string{"plain", "\x1b[38;2;255;135;0;1mhot\x1b[0m", "\x1b[38:5:196;48;5;21mx", "\x1b[58:2::1:2:3;4:3m", "a\x1b[2Jb\x1b]0;title\x07c", "\x1b[38;5m\x1b[m", "unterminated \x1b[3"} {
+		f.Add(s, uint8(1))
+	}
+
+	f.Fuzz(func(t *testing.T, s string, n uint8) {
+		level := glint.Level(n % 4)
+
+		plain := glint.Strip(s)
+		if strings.IndexByte(plain, ansi.ESC) >= 0 {
+			t.Fatalf("Strip(%q) left an escape: %q", s, plain)
+		}
+		if got := glint.Strip(glint.Downsample(s, level)); got != plain {
+			t.Fatalf("Downsample should only change escape sequences, Strip gives %q instead of %q", got, plain)
+		}
+		// Escape sequences end grapheme clusters and invalid bytes, so stripping them can only join text into fewer cells
+		if glint.Width(plain) > glint.Width(s) {
+			t.Fatalf("Width(%q) should ignore escape sequences, got %d for the text %q alone", s, glint.Width(plain), plain)
+		}
+		cells, _ := ansi.Cells(s)
+		width := 0
+		for _, cell := range cells {
+			width += cell.Width
+		}
+		if width != glint.Width(s) {
+			t.Fatalf("Width(%q) is %d, while its cells take %d", s, glint.Width(s), width)
+		}
+
+		var buf bytes.Buffer
+		if err := glint.ToPlain(iotest.OneByteReader(strings.NewReader(s)), &buf); err != nil || buf.String() != plain {
+			t.Fatalf("ToPlain should match Strip, got %q instead of %q (%v)", buf.String(), plain, err)
+		}
+		buf.Reset()
+		if err := glint.ToLevel(iotest.OneByteReader(strings.NewReader(s)), &buf, level); err != nil || glint.Strip(buf.String()) != plain {
+			t.Fatalf("ToLevel should only change escape sequences, got %q (%v)", buf.String(), err)
+		}
+
+		if got := glinttest.Visible(plain); got != plain {
+			t.Fatalf("Visible should leave plain text unchanged, got %q", got)
+		}
+		glinttest.Annotate(s, level)
+	})
+}
+
+// FuzzTextLayout checks that the layout helpers respect the requested width
+func FuzzTextLayout(f *testing.F) {
+	for _, s := range []string{"hello world", "\x1b[1m日本語のテキスト\x1b[0m", "\x1b]8;;https://x\x1b\\link text\x1b]8;;\x1b\\", "a\tb\nc"} {
+		f.Add(s, uint8(5))
+	}
+
+	f.Fuzz(func(t *testing.T, s string, n uint8) {
+		width := int(n%84) - 2
+
+		if got := glint.Truncate(s, width, "…"); glint.Width(got) > max(width, 0) {
+			t.Fatalf("Truncate(%q, %d) is %d cells wide: %q", s, width, glint.Width(got), got)
+		}
+		// Every line holds at least one character, which may be two cells wide
+		if width >= 2 {
+			for _, line := range visibleLines(glint.Wrap(s, width)) {
+				if glint.Width(line) > width {
+					t.Fatalf("Wrap(%q, %d) gave a line %d cells wide: %q", s, width, glint.Width(line), line)
+				}
+			}
+		}
+
+		// An unterminated escape sequence at the end swallows the padding, like any text that follows it
+		if complete := glint.Width(s+" ") > glint.Width(s); !complete {
+			return
+		}
+		if got := glint.PadRight(s, width); glint.Width(got) < width {
+			t.Fatalf("PadRight(%q, %d) is only %d cells wide", s, width, glint.Width(got))
+		}
+	})
+}
+
+// visibleLines splits s at the newlines of its text, leaving those inside escape sequences such as SOS strings.
+func visibleLines(s string) []string {
+	var lines []string
+	start, offset := 0, 0
+	for offset < len(s) {
+		tok, ok := ansi.Next(s[offset:])
+		if !ok {
+			break
+		}
+		if tok.Kind == ansi.TokenText {
+			for i := 0; i < len(tok.Raw); i++ {
+				if tok.Raw[i] == '\n' {
+					lines = append(lines, s[start:offset+i])
+					start = offset + i + 1
+				}
+			}
+		}
+		offset += len(tok.Raw)
+	}
+	return append(lines, s[start:])
+}
+
+// FuzzParseColorReport checks parsing of terminal replies to color queries
+func FuzzParseColorReport(f *testing.F) {
+	for _, s := range []string{"\x1b]11;rgb:ffff/ffff/ffff\x1b\\", "\x1b]10;rgb:1c/2d/3e\x07", "\x1b]4;1;rgb:f/0/0\x07", "\x1b]11;rgba:0/0/0/0\x07", "\x1b]11;rgb:/0/0\x07"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		color, ok := ansi.ParseColorReport(s)
+		if !ok {
+			return
+		}
+		if color.Kind != core.ColorRGB {
+			t.Fatalf("ParseColorReport(%q) should give an RGB color, got %+v", s, color)
+		}
+		if tok, _ := ansi.Next(s); tok.Kind != ansi.TokenOSC {
+			t.Fatalf("ParseColorReport(%q) accepted a reply that is not an OSC sequence", s)
+		}
+	})
+}
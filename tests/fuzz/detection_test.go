@@ -0,0 +1,98 @@
+package fuzz
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// FuzzTerminalColorLevel checks detection over arbitrary combinations of the variables it reads
+func FuzzTerminalColorLevel(f *testing.F) {
+	f.Add("xterm-256color", "truecolor", "", "", "", "", "", "")
+	f.Add("dumb", "truecolor", "", "", "", "", "", "")
+	f.Add("xterm", "", "1", "1", "", "", "1", "true")
+	f.Add("", "", "", "0", "1", "", "", "")
+	f.Add("screen", "256color", "", "", "", "1", "", "")
+
+	config := "XDG_CONFIG_HOME=" + f.TempDir()
+	f.Fuzz(func(t *testing.T, term, colorTerm, noColor, forceColor, color16, color256, color24, ci string) {
+		env := []string{
+			config,
+			"TERM=" + term,
+			"COLORTERM=" + colorTerm,
+			"NO_COLOR=" + noColor,
+			"FORCE_COLOR=" + forceColor,
+			"COLOR_16=" + color16,
+			"COLOR_256=" + color256,
+			"COLOR_24=" + color24,
+			"CI=" + ci,
+		}
+
+		level, rule := core.ExplainColorLevelEnv(env)
+		if level < core.LevelNone || level > core.LevelTrue {
+			t.Fatalf("level %d is out of range", level)
+		}
+		if rule == "" {
+			t.Fatalf("detection of level %v should name a rule", level)
+		}
+		if noColor != "" && (level != core.LevelNone || rule != "NO_COLOR") {
+			t.Fatalf("NO_COLOR should always win, got %v by %q", level, rule)
+		}
+		if again, againRule := core.ExplainColorLevelEnv(env); again != level || againRule != rule {
+			t.Fatalf("detection should be deterministic, got %v by %q and %v by %q", level, rule, again, againRule)
+		}
+
+		e := glint.DetectWithEnv(env)
+		if e.Supported != (e.Level != glint.LevelNone) || e.Supported && e.Level < glint.Level16 {
+			t.Fatalf("DetectWithEnv should report consistent results, got %+v", e)
+		}
+	})
+}
+
+// FuzzConfig checks detection with arbitrary config file contents
+func FuzzConfig(f *testing.F) {
+	f.Add("level = 256\n")
+	f.Add("# comment\nmode = never\n[mytool]\nlevel = truecolor\n")
+	f.Add("term.xterm-kitty = \"truecolor\"\n[global]\nlevel=16 ; trailing\n")
+	f.Add("[\n=\n[]]\nmode = \"\n")
+
+	f.Fuzz(func(t *testing.T, content string) {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, "glint"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "glint", "config"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		env := []string{"XDG_CONFIG_HOME=" + dir, "TERM=xterm-kitty"}
+		level, rule := core.ExplainColorLevelEnv(env)
+		if level < core.LevelNone || level > core.LevelTrue || rule == "" {
+			t.Fatalf("detection gave %v by %q", level, rule)
+		}
+
+		if level, rule := core.ExplainColorLevelEnv(append(env, "NO_COLOR=1")); level != core.LevelNone || rule != "NO_COLOR" {
+			t.Fatalf("NO_COLOR should win over the config file, got %v by %q", level, rule)
+		}
+	})
+}
+
+// FuzzParseLevel checks that every accepted level name round trips through LevelName
+func FuzzParseLevel(f *testing.F) {
+	for _, s := range []string{"none", "16", "256", "truecolor", "24bit", "ANSI256", " 256 ", ""} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		level, err := glint.ParseLevel(s)
+		if err != nil {
+			return
+		}
+		if again, err := glint.ParseLevel(glint.LevelName(level)); err != nil || again != level {
+			t.Fatalf("ParseLevel(%q) gave %v, which does not round trip: %v (%v)", s, level, again, err)
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var (
+	cliPath string    // cliPath is the glint binary built for the tests
+	cliErr  error     // cliErr records why the binary could not be built
+	cliOnce sync.Once // cliOnce builds the binary for the first test that needs it
+)
+
+// TestMain removes the glint binary after the tests. When started by the pty harness it runs the detection helper instead.
+func TestMain(m *testing.M) {
+	if os.Getenv(ptyHelperEnv) != "" {
+		os.Exit(ptyHelper())
+	}
+
+	code := m.Run()
+	if cliPath != "" {
+		os.RemoveAll(filepath.Dir(cliPath))
+	}
+	os.Exit(code)
+}
+
+// buildCLI builds cmd/glint once per test run and returns the path of the binary.
+func buildCLI(t *testing.T) string {
+	t.Helper()
+
+	cliOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "glint-cli")
+		if err != nil {
+			cliErr = err
+			return
+		}
+		cliPath = filepath.Join(dir, "glint")
+		out, err := exec.Command("go", "build", "-o", cliPath, "github.com/droqsic/glint/cmd/glint").CombinedOutput()
+		if err != nil {
+			cliErr = fmt.Errorf("%v: %s", err, out)
+		}
+	})
+	if cliErr != nil {
+		t.Skipf("cannot build cmd/glint: %v", cliErr)
+	}
+	return cliPath
+}
+
+// runCLI runs the glint binary with args, stdin and extra environment variables, and returns its output and exit code.
+func runCLI(t *testing.T, stdin string, env []string, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+
+	cmd := exec.Command(buildCLI(t), args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Env = append(os.Environ(), env...)
+	var out, errOut bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &errOut
+
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return out.String(), errOut.String(), exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatalf("running glint: %v", err)
+	}
+	return out.String(), errOut.String(), 0
+}
+
+// TestCLIInfo tests the diagnostic report in both formats
+func TestCLIInfo(t *testing.T) {
+	env := []string{"TERM=xterm-256color", "NO_COLOR=", "GITHUB_ACTIONS=true", "TMUX=/tmp/tmux-0/default,1,0", "INSIDE_EMACS=", "NVIM=", "VIM_TERMINAL=901", "XDG_CONFIG_HOME=" + t.TempDir()}
+
+	out, _, code := runCLI(t, "", env, "--json")
+	if code != 0 {
+		t.Fatalf("glint --json should succeed, got exit code %d", code)
+	}
+
+	var report struct {
+		Supported   bool   `json:"supported"`
+		Level       string `json:"level"`
+		Rule        string `json:"rule"`
+		Environment struct {
+			Supported bool   `json:"supported"`
+			Level     string `json:"level"`
+			Rule      string `json:"rule"`
+		} `json:"environment"`
+		Multiplexer string            `json:"multiplexer"`
+		Embedding   string            `json:"embedding"`
+		CI          string            `json:"ci"`
+		Env         map[string]string `json:"env"`
+		TTY         map[string]bool   `json:"tty"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("glint --json should write JSON: %v\n%s", err, out)
+	}
+	if report.Supported || report.Rule != "stdout is not a terminal" {
+		t.Errorf("A piped stdout should be reported as unsupported, got %+v", report)
+	}
+	if e := report.Environment; !e.Supported || e.Level != "256" || e.Rule != "TERM=xterm-256color" {
+		t.Errorf("A redirected report should still give the level of the environment, got %+v", e)
+	}
+	if report.Multiplexer != "tmux" || report.CI != "github-actions" || report.Embedding != "vim" {
+		t.Errorf("Multiplexer, CI and embedding should be tmux, github-actions and vim, got %q, %q and %q", report.Multiplexer, report.CI, report.Embedding)
+	}
+	if report.Env["TERM"] != "xterm-256color" || report.TTY["stdout"] {
+		t.Errorf("Report should include TERM and the TTY status, got %+v", report)
+	}
+
+	out, _, code = runCLI(t, "", env, "info")
+	if code != 0 || !strings.Contains(out, "Environment") || !strings.Contains(out, "TERM") {
+		t.Errorf("glint info should print a text report, got exit code %d:\n%s", code, out)
+	}
+
+	_, stderr, code := runCLI(t, "", nil, "bogus")
+	if code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Errorf("Unknown commands should fail with exit code 2, got %d: %s", code, stderr)
+	}
+}
+
+// TestCLIPalette tests that the test patterns are rendered at the requested level
+func TestCLIPalette(t *testing.T) {
+	tests := []struct {
+		level    string
+		contains []string
+		excludes []string
+	}{
+		{"truecolor", []string{"48;2;255;0;0", "48;5;196", "\x1b[53moverline"}, nil},
+		{"256", []string{"48;5;196", "48;5;232"}, []string{"48;2;"}},
+		{"16", []string{"\x1b[41m", "\x1b[1mbold"}, []string{"48;5;", "48;2;"}},
+		{"none", []string{"strikethrough"}, []string{"\x1b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.level, func(t *testing.T) {
+			out, _, code := runCLI(t, "", nil, "palette", "--level", test.level)
+			if code != 0 {
+				t.Fatalf("glint palette should succeed, got exit code %d", code)
+			}
+			for _, s := range test.contains {
+				if !strings.Contains(out, s) {
+					t.Errorf("Output at %s should contain %q", test.level, s)
+				}
+			}
+			for _, s := range test.excludes {
+				if strings.Contains(out, s) {
+					t.Errorf("Output at %s should not contain %q", test.level, s)
+				}
+			}
+		})
+	}
+
+	if _, stderr, code := runCLI(t, "", nil, "palette", "--level", "rainbow"); code != 2 || !strings.Contains(stderr, "invalid color level") {
+		t.Errorf("An invalid level should fail with exit code 2, got %d: %s", code, stderr)
+	}
+}
+
+// TestCLIFilters tests the strip, convert, html and svg commands
+func TestCLIFilters(t *testing.T) {
+	input := "\x1b[1;38;2;255;0;0merror\x1b[0m: <done>\n"
+
+	tests := []struct {
+		args     []string
+		expected string
+		contains string
+	}{
+		{[]string{"strip"}, "error: <done>\n", ""},
+		{[]string{"convert", "--to", "256"}, "\x1b[1;38;5;196merror\x1b[0m: <done>\n", ""},
+		{[]string{"convert", "--to", "none"}, "\x1b[1merror\x1b[0m: <done>\n", ""},
+		{[]string{"html", "--fragment"}, "", "&lt;done&gt;"},
+		{[]string{"html", "--standalone", "--classes"}, "", "<style>"},
+		{[]string{"svg", "--title", "demo"}, "", ">demo</text>"},
+	}
+
+	for _, test := range tests {
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			out, stderr, code := runCLI(t, input, nil, test.args...)
+			if code != 0 {
+				t.Fatalf("glint %v should succeed, got exit code %d: %s", test.args, code, stderr)
+			}
+			if test.expected != "" && out != test.expected {
+				t.Errorf("glint %v should write %q, got %q", test.args, test.expected, out)
+			}
+			if test.contains != "" && !strings.Contains(out, test.contains) {
+				t.Errorf("glint %v output should contain %q, got:\n%s", test.args, test.contains, out)
+			}
+		})
+	}
+
+	for _, args := range [][]string{{"convert", "--to", "rainbow"}, {"html", "--palette", "rainbow"}} {
+		if _, _, code := runCLI(t, input, nil, args...); code != 2 {
+			t.Errorf("glint %v should fail with exit code 2, got %d", args, code)
+		}
+	}
+}
+
+// TestCLIRun tests running a command under a pseudo-terminal
+func TestCLIRun(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("glint run needs pseudo-terminals, which are only supported on Linux")
+	}
+
+	script := `test -t 1 && printf '\033[38;2;255;0;0mtty\033[0m '; stty size; exit 5`
+
+	out, _, code := runCLI(t, "", nil, "run", "--columns", "90", "--rows", "20", "--", "sh", "-c", script)
+	if code != 5 {
+		t.Errorf("glint run should pass on the exit code 5, got %d", code)
+	}
+	if out != "\x1b[38;2;255;0;0mtty\x1b[0m 20 90\n" {
+		t.Errorf("Child should see a 90x20 terminal, got %q", out)
+	}
+
+	out, _, _ = runCLI(t, "", []string{"TERM=dumb"}, "run", "--", "sh", "-c", "echo $TERM")
+	if out != "xterm-256color\n" {
+		t.Errorf("glint run should replace TERM=dumb for the child, got %q", out)
+	}
+
+	out, _, _ = runCLI(t, "", nil, "run", "--to", "16", "--", "sh", "-c", script)
+	if !strings.HasPrefix(out, "\x1b[91mtty\x1b[0m ") {
+		t.Errorf("glint run --to 16 should downsample the output, got %q", out)
+	}
+
+	if _, _, code := runCLI(t, "", nil, "run", "--", "sh", "-c", "kill -TERM $$"); code != 128+15 {
+		t.Errorf("A child killed by SIGTERM should give exit code 143, got %d", code)
+	}
+	if _, _, code := runCLI(t, "", nil, "run", "--", "glint-no-such-command"); code != 127 {
+		t.Errorf("A missing command should give exit code 127, got %d", code)
+	}
+}
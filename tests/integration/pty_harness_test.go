@@ -0,0 +1,218 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/ansi"
+	"github.com/droqsic/glint/pty"
+)
+
+// ptyHelperEnv makes the test binary run ptyHelper instead of the tests.
+const ptyHelperEnv = "GLINT_PTY_HELPER"
+
+// deviceAttributes is the primary device attributes request glint sends after every query, and the harness's reply.
+const (
+	deviceAttributes      = "\x1b[c"
+	deviceAttributesReply = "\x1b[?62;22c"
+)
+
+// ptyResult is what the helper detects on its terminal.
+type ptyResult struct {
+	Supported  bool   `json:"supported"`
+	Level      int    `json:"level"`
+	Rule       string `json:"rule"`
+	Background string `json:"background"`
+	Columns    int    `json:"columns"`
+	Rows       int    `json:"rows"`
+}
+
+// ptyHelper runs color and background detection and prints the results as JSON. It runs in the child process.
+func ptyHelper() int {
+	e := glint.Explain()
+	result := ptyResult{Supported: glint.ColorSupport(), Level: int(glint.ColorLevel()), Rule: e.Rule}
+	result.Background = glint.DetectBackground().String()
+	result.Columns, result.Rows, _ = glint.TerminalSize()
+
+	if err := json.NewEncoder(os.Stdout).Encode(result); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// ptyReply scripts the terminal's answer to a query.
+type ptyReply struct {
+	query string // query is the escape sequence the helper writes
+	reply string // reply is written back to the helper
+}
+
+// ptySession is the outcome of running the helper on a pseudo-terminal.
+type ptySession struct {
+	result    ptyResult // result is what the helper detected
+	text      string    // text is everything the helper wrote outside escape sequences
+	sequences []string  // sequences are the escape sequences the helper wrote, in order
+}
+
+// runPtyHelper runs the helper with stdin, stdout and stderr attached to a pseudo-terminal of the given size and env as
+// its whole environment. The harness plays the terminal: it answers each query in replies and every device attributes
+// request, and records what the helper wrote.
+func runPtyHelper(t *testing.T, env []string, size pty.Size, replies ...ptyReply) ptySession {
+	t.Helper()
+
+	if runtime.GOOS != "linux" {
+		t.Skip("the pty harness needs pseudo-terminals, which are only supported on Linux")
+	}
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append([]string{ptyHelperEnv + "=1", "XDG_CONFIG_HOME=" + t.TempDir()}, env...)
+	term, err := pty.Start(cmd, size)
+	if err != nil {
+		t.Fatalf("pty.Start returned error: %v", err)
+	}
+	defer term.Close()
+
+	timer := time.AfterFunc(10*time.Second, func() { cmd.Process.Kill() })
+	defer timer.Stop()
+
+	var session ptySession
+	var pending, text strings.Builder
+	buf := make([]byte, 1024)
+	for {
+		n, err := term.Read(buf)
+		pending.Write(buf[:n])
+
+		s := pending.String()
+		for {
+			tok, ok := ansi.Next(s)
+			if !ok {
+				break
+			}
+			s = s[len(tok.Raw):]
+
+			if tok.Kind == ansi.TokenText {
+				text.WriteString(tok.Raw)
+				continue
+			}
+			session.sequences = append(session.sequences, tok.Raw)
+			for _, r := range replies {
+				if tok.Raw == r.query {
+					term.Write([]byte(r.reply))
+				}
+			}
+			if tok.Raw == deviceAttributes {
+				term.Write([]byte(deviceAttributesReply))
+			}
+		}
+		pending.Reset()
+		pending.WriteString(s)
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading the terminal failed: %v", err)
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("helper failed: %v\n%s", err, text.String())
+	}
+
+	// The terminal translates newlines into carriage return and newline pairs.
+	session.text = strings.ReplaceAll(text.String(), "\r\n", "\n")
+	if err := json.Unmarshal([]byte(session.text), &session.result); err != nil {
+		t.Fatalf("helper output %q is not a result: %v", session.text, err)
+	}
+	return session
+}
+
+// TestPtyDetection tests color detection with stdout attached to a real terminal
+func TestPtyDetection(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       []string
+		supported bool
+		level     glint.Level
+		rule      string
+	}{
+		{"TrueColor", []string{"TERM=xterm-256color", "COLORTERM=truecolor"}, true, glint.LevelTrue, "COLORTERM=truecolor"},
+		{"256Colors", []string{"TERM=xterm-256color"}, true, glint.Level256, "TERM=xterm-256color"},
+		{"Basic", []string{"TERM=xterm"}, true, glint.Level16, "TERM=xterm"},
+		{"Dumb", []string{"TERM=dumb"}, false, glint.LevelNone, "TERM=dumb"},
+		{"NoColor", []string{"TERM=xterm-256color", "NO_COLOR=1"}, false, glint.LevelNone, "NO_COLOR"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := runPtyHelper(t, test.env, pty.Size{Columns: 80, Rows: 24})
+
+			result := session.result
+			if result.Supported != test.supported || glint.Level(result.Level) != test.level || result.Rule != test.rule {
+				t.Errorf("On a terminal with %v detection should give %v, %v by %q, got %v, %v by %q", test.env,
+					test.supported, test.level, test.rule, result.Supported, glint.Level(result.Level), result.Rule)
+			}
+		})
+	}
+
+	t.Run("Size", func(t *testing.T) {
+		session := runPtyHelper(t, []string{"TERM=xterm"}, pty.Size{Columns: 132, Rows: 43})
+		if session.result.Columns != 132 || session.result.Rows != 43 {
+			t.Errorf("TerminalSize should report 132x43, got %dx%d", session.result.Columns, session.result.Rows)
+		}
+	})
+
+	t.Run("NotTerminal", func(t *testing.T) {
+		cmd := exec.Command(os.Args[0])
+		cmd.Env = []string{ptyHelperEnv + "=1", "TERM=xterm-256color", "XDG_CONFIG_HOME=" + t.TempDir()}
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("helper failed: %v", err)
+		}
+
+		var result ptyResult
+		if err := json.Unmarshal(out, &result); err != nil {
+			t.Fatalf("helper output %q is not a result: %v", out, err)
+		}
+		if result.Supported || result.Rule != "stdout is not a terminal" {
+			t.Errorf("A pipe should not support color, got %v by %q", result.Supported, result.Rule)
+		}
+	})
+}
+
+// TestPtyBackgroundQuery tests background detection answering the OSC 11 query with scripted replies
+func TestPtyBackgroundQuery(t *testing.T) {
+	const query = "\x1b]11;?\x1b\\"
+
+	tests := []struct {
+		name     string
+		env      []string
+		replies  []ptyReply
+		expected string
+	}{
+		{"Light", nil, []ptyReply{{query, "\x1b]11;rgb:ffff/ffff/ffff\x1b\\"}}, "light"},
+		{"Dark", []string{"COLORFGBG=0;15"}, []ptyReply{{query, "\x1b]11;rgb:1c1c/1c1c/1c1c\x07"}}, "dark"},
+		{"Unanswered", []string{"COLORFGBG=0;15"}, nil, "light"},
+		{"Default", nil, nil, "dark"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := runPtyHelper(t, append([]string{"TERM=xterm-256color"}, test.env...), pty.Size{Columns: 80, Rows: 24}, test.replies...)
+
+			if session.result.Background != test.expected {
+				t.Errorf("Background should be %s, got %s", test.expected, session.result.Background)
+			}
+			if want := []string{query, deviceAttributes}; strings.Join(session.sequences, "") != strings.Join(want, "") {
+				t.Errorf("The helper should write the background query followed by a device attributes request, got %q", session.sequences)
+			}
+		})
+	}
+}
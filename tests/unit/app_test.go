@@ -0,0 +1,113 @@
+package unit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/glinttest"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// TestSetAppName tests that application scoped variables take precedence over the generic ones
+func TestSetAppName(t *testing.T) {
+	defer func() {
+		glint.SetAppName("")
+		core.ClearCache()
+		glint.ResetColor()
+	}()
+
+	glint.ResetColor()
+	glint.SetAppName("my-tool")
+
+	if colorKey, levelKey := core.AppEnvKeys(); colorKey != "MY_TOOL_COLOR" || levelKey != "MY_TOOL_COLOR_LEVEL" {
+		t.Fatalf("SetAppName should register MY_TOOL_COLOR and MY_TOOL_COLOR_LEVEL, got %q and %q", colorKey, levelKey)
+	}
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		supported bool
+		level     glint.Level
+	}{
+		{"Never", map[string]string{"MY_TOOL_COLOR": "never", "FORCE_COLOR": "3"}, false, glint.LevelNone},
+		{"NeverBeatsLevel", map[string]string{"MY_TOOL_COLOR": "never", "MY_TOOL_COLOR_LEVEL": "256"}, false, glint.LevelNone},
+		{"LevelNeedsTerminal", map[string]string{"MY_TOOL_COLOR_LEVEL": "256", "COLORTERM": "truecolor"}, false, glint.LevelNone},
+		{"AlwaysWithLevel", map[string]string{"MY_TOOL_COLOR": "always", "MY_TOOL_COLOR_LEVEL": "256", "COLORTERM": "truecolor"}, true, glint.Level256},
+		{"ModeLevel", map[string]string{"MY_TOOL_COLOR": "16"}, true, glint.Level16},
+		{"Always", map[string]string{"MY_TOOL_COLOR": "always", "TERM": "dumb", "NO_COLOR": "1"}, true, glint.Level16},
+		{"AlwaysKeepsGenericLevel", map[string]string{"MY_TOOL_COLOR": "always", "COLORTERM": "truecolor"}, true, glint.LevelTrue},
+		{"AutoFallsBack", map[string]string{"MY_TOOL_COLOR": "auto", "NO_COLOR": "1"}, false, glint.LevelNone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, key := range []string{"MY_TOOL_COLOR", "MY_TOOL_COLOR_LEVEL", "NO_COLOR", "FORCE_COLOR", "COLORTERM", "TERM"} {
+				t.Setenv(key, test.env[key])
+			}
+			core.ClearCache()
+			glint.ResetColor()
+
+			if glint.ColorSupport() != test.supported {
+				t.Errorf("ColorSupport() should be %v", test.supported)
+			}
+			if level := glint.ColorLevel(); level != test.level {
+				t.Errorf("ColorLevel() should be %v, got %v", test.level, level)
+			}
+		})
+	}
+
+	t.Run("ForcedWins", func(t *testing.T) {
+		t.Setenv("MY_TOOL_COLOR", "never")
+		t.Setenv("NO_COLOR", "")
+		core.ClearCache()
+
+		glint.ForceLevel(glint.Level256)
+		if level := glint.ColorLevel(); level != glint.Level256 {
+			t.Errorf("ForceLevel should win over MY_TOOL_COLOR, got %v", level)
+		}
+	})
+}
+
+// TestAppLevelOnTerminal tests that the level variable chooses the level of a terminal without forcing color
+func TestAppLevelOnTerminal(t *testing.T) {
+	defer glint.SetAppName("")
+	glint.SetAppName("my-tool")
+
+	env := map[string]string{"MY_TOOL_COLOR_LEVEL": "truecolor", "NO_COLOR": "1"}
+	glinttest.Terminal(t, glinttest.Options{Level: glint.Level16, TTY: true, Env: env})
+	if e := glint.Explain(); !e.Supported || e.Level != glint.LevelTrue || e.Rule != "MY_TOOL_COLOR_LEVEL=truecolor" {
+		t.Errorf("MY_TOOL_COLOR_LEVEL should choose the level of a terminal over NO_COLOR, got %+v", e)
+	}
+
+	glinttest.Terminal(t, glinttest.Options{Level: glint.Level16, Env: env})
+	if e := glint.Explain(); e.Supported || e.Rule != "stdout is not a terminal" {
+		t.Errorf("MY_TOOL_COLOR_LEVEL should not force color into a pipe, got %+v", e)
+	}
+	if glint.ColorSupportFor(&bytes.Buffer{}) {
+		t.Errorf("MY_TOOL_COLOR_LEVEL should not force color into a buffer")
+	}
+}
+
+// TestParseLevel tests parsing color level names
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		value string
+		level core.Level
+		ok    bool
+	}{
+		{"none", core.LevelNone, true},
+		{"16", core.Level16, true},
+		{" 256 ", core.Level256, true},
+		{"TrueColor", core.LevelTrue, true},
+		{"24bit", core.LevelTrue, true},
+		{"", core.LevelNone, false},
+		{"rainbow", core.LevelNone, false},
+	}
+
+	for _, test := range tests {
+		if level, ok := core.ParseLevel(test.value); level != test.level || ok != test.ok {
+			t.Errorf("ParseLevel(%q) should give %v, %v, got %v, %v", test.value, test.level, test.ok, level, ok)
+		}
+	}
+}
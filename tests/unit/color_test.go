@@ -0,0 +1,154 @@
+package unit
+
+import (
+	"os"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/ansi"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// TestColorConstructors tests the Color constructors and Sequence
+func TestColorConstructors(t *testing.T) {
+	tests := []struct {
+		name       string
+		color      glint.Color
+		foreground string
+		background string
+	}{
+		{"Default", glint.Color{}, "", ""},
+		{"ANSI", glint.ANSI(1), "\x1b[31m", "\x1b[41m"},
+		{"ANSIBright", glint.ANSI(12), "\x1b[94m", "\x1b[104m"},
+		{"ANSI256", glint.ANSI256(208), "\x1b[38;5;208m", "\x1b[48;5;208m"},
+		{"RGB", glint.RGB(1, 2, 3), "\x1b[38;2;1;2;3m", "\x1b[48;2;1;2;3m"},
+		{"Hex", glint.Hex("#ff8700"), "\x1b[38;2;255;135;0m", "\x1b[48;2;255;135;0m"},
+		{"HexShort", glint.Hex("f80"), "\x1b[38;2;255;136;0m", "\x1b[48;2;255;136;0m"},
+		{"HexInvalid", glint.Hex("#zzzzzz"), "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := test.color.Sequence(false); result != test.foreground {
+				t.Errorf("Sequence(false) should return %q, got %q", test.foreground, result)
+			}
+			if result := test.color.Sequence(true); result != test.background {
+				t.Errorf("Sequence(true) should return %q, got %q", test.background, result)
+			}
+		})
+	}
+}
+
+// TestColorResolve tests downsampling a Color to a color level
+func TestColorResolve(t *testing.T) {
+	color := glint.Hex("#ff8700")
+
+	tests := []struct {
+		level    glint.Level
+		expected string
+	}{
+		{glint.LevelTrue, "\x1b[38;2;255;135;0m"},
+		{glint.Level256, "\x1b[38;5;208m"},
+		{glint.Level16, "\x1b[33m"},
+		{glint.LevelNone, ""},
+	}
+
+	for _, test := range tests {
+		if result := color.Resolve(test.level).Sequence(false); result != test.expected {
+			t.Errorf("Resolve(%v) should produce %q, got %q", test.level, test.expected, result)
+		}
+	}
+}
+
+// TestAdaptiveColor tests choosing the AdaptiveColor variant for the background
+func TestAdaptiveColor(t *testing.T) {
+	defer glint.SetBackground(glint.BackgroundAuto)
+
+	color := glint.AdaptiveColor{Light: glint.ANSI(0), Dark: glint.ANSI(15)}
+
+	glint.SetBackground(glint.BackgroundDark)
+	if !glint.HasDarkBackground() {
+		t.Errorf("HasDarkBackground should be true after SetBackground(BackgroundDark)")
+	}
+	if result := color.Resolve(glint.Level16); result != glint.ANSI(15) {
+		t.Errorf("AdaptiveColor should use Dark on a dark background, got %v", result)
+	}
+
+	glint.SetBackground(glint.BackgroundLight)
+	if result := color.Resolve(glint.Level16); result != glint.ANSI(0) {
+		t.Errorf("AdaptiveColor should use Light on a light background, got %v", result)
+	}
+	if result := color.Resolve(glint.LevelNone); !result.IsDefault() {
+		t.Errorf("AdaptiveColor should resolve to the default color at LevelNone, got %v", result)
+	}
+}
+
+// TestBackgroundFromEnv tests parsing the COLORFGBG environment variable
+func TestBackgroundFromEnv(t *testing.T) {
+	original, had := os.LookupEnv("COLORFGBG")
+	defer func() {
+		if had {
+			os.Setenv("COLORFGBG", original)
+		} else {
+			os.Unsetenv("COLORFGBG")
+		}
+		core.ClearCache()
+	}()
+
+	tests := []struct {
+		value string
+		dark  bool
+		ok    bool
+	}{
+		{"15;0", true, true},
+		{"0;15", false, true},
+		{"0;7", false, true},
+		{"15;default;8", true, true},
+		{"", false, false},
+		{"15;bogus", false, false},
+		{"15;42", false, false},
+	}
+
+	for _, test := range tests {
+		os.Setenv("COLORFGBG", test.value)
+		core.ClearCache()
+
+		dark, ok := core.BackgroundFromEnv()
+		if dark != test.dark || ok != test.ok {
+			t.Errorf("BackgroundFromEnv with COLORFGBG=%q should return (%v, %v), got (%v, %v)", test.value, test.dark, test.ok, dark, ok)
+		}
+	}
+}
+
+// TestParseColorReport tests parsing replies to OSC color queries
+func TestParseColorReport(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		color core.Color
+		ok    bool
+	}{
+		{"FourDigits", "\x1b]11;rgb:ffff/ffff/ffff\x1b\\", core.Color{Kind: core.ColorRGB, R: 255, G: 255, B: 255}, true},
+		{"TwoDigitsBEL", "\x1b]11;rgb:28/2a/36\x07", core.Color{Kind: core.ColorRGB, R: 0x28, G: 0x2a, B: 0x36}, true},
+		{"OneDigit", "\x1b]11;rgb:f/0/8\x1b\\", core.Color{Kind: core.ColorRGB, R: 255, G: 0, B: 136}, true},
+		{"NotRGB", "\x1b]11;cmy:1/1/1\x1b\\", core.Color{}, false},
+		{"Truncated", "\x1b]11;rgb:ffff/ffff", core.Color{}, false},
+		{"Empty", "", core.Color{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			color, ok := ansi.ParseColorReport(test.reply)
+			if color != test.color || ok != test.ok {
+				t.Errorf("ParseColorReport(%q) should return (%+v, %v), got (%+v, %v)", test.reply, test.color, test.ok, color, ok)
+			}
+		})
+	}
+
+	if dark := (core.Color{Kind: core.ColorRGB, R: 0x28, G: 0x2a, B: 0x36}).IsDark(); !dark {
+		t.Errorf("Dracula background should be dark")
+	}
+	if dark := (core.Color{Kind: core.ColorRGB, R: 0xfd, G: 0xf6, B: 0xe3}).IsDark(); dark {
+		t.Errorf("Solarized light background should be light")
+	}
+}
@@ -0,0 +1,143 @@
+package unit
+
+import (
+	"encoding"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// TestColorModeSet tests parsing ColorMode values
+func TestColorModeSet(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected glint.ColorMode
+	}{
+		{"auto", glint.ColorModeAuto},
+		{"ALWAYS", glint.ColorModeAlways},
+		{"", glint.ColorModeAlways},
+		{"never", glint.ColorModeNever},
+		{"none", glint.ColorModeNever},
+		{"16", glint.ColorMode16},
+		{"256", glint.ColorMode256},
+		{"truecolor", glint.ColorModeTrue},
+		{"24bit", glint.ColorModeTrue},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			var mode glint.ColorMode
+			if err := mode.Set(test.value); err != nil {
+				t.Fatalf("Set(%q) returned error: %v", test.value, err)
+			}
+			if mode != test.expected {
+				t.Errorf("Set(%q) should give %v, got %v", test.value, test.expected, mode)
+			}
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		mode := glint.ColorMode256
+		if err := mode.Set("rainbow"); err == nil {
+			t.Errorf("Set should reject unknown values")
+		}
+		if mode != glint.ColorMode256 {
+			t.Errorf("Set should leave the mode unchanged on error, got %v", mode)
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, mode := range []glint.ColorMode{glint.ColorModeAuto, glint.ColorModeAlways, glint.ColorModeNever, glint.ColorMode16, glint.ColorMode256, glint.ColorModeTrue} {
+			var parsed glint.ColorMode
+			if err := parsed.Set(mode.String()); err != nil || parsed != mode {
+				t.Errorf("Set(%q) should round trip to %v, got %v (%v)", mode.String(), mode, parsed, err)
+			}
+		}
+	})
+}
+
+// TestColorModeInterfaces tests ColorMode with the flag and encoding packages
+func TestColorModeInterfaces(t *testing.T) {
+	var _ flag.Value = new(glint.ColorMode)
+	var _ encoding.TextUnmarshaler = new(glint.ColorMode)
+	var _ encoding.TextMarshaler = glint.ColorModeAuto
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var mode glint.ColorMode
+	fs.Var(&mode, "color", "when to use colors")
+	if err := fs.Parse([]string{"--color=256"}); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if mode != glint.ColorMode256 {
+		t.Errorf("Flag parsing should set ColorMode256, got %v", mode)
+	}
+
+	if err := mode.UnmarshalText([]byte("never")); err != nil || mode != glint.ColorModeNever {
+		t.Errorf("UnmarshalText should set ColorModeNever, got %v (%v)", mode, err)
+	}
+	if text, _ := mode.MarshalText(); string(text) != "never" {
+		t.Errorf("MarshalText should return never, got %q", text)
+	}
+}
+
+// TestColorModeApply tests configuring detection with ColorMode.Apply
+func TestColorModeApply(t *testing.T) {
+	originalNoColor, hadNoColor := os.LookupEnv("NO_COLOR")
+	defer func() {
+		if hadNoColor {
+			os.Setenv("NO_COLOR", originalNoColor)
+		} else {
+			os.Unsetenv("NO_COLOR")
+		}
+		core.ClearCache()
+		glint.ResetColor()
+	}()
+
+	os.Unsetenv("NO_COLOR")
+	core.ClearCache()
+
+	tests := []struct {
+		mode      glint.ColorMode
+		supported bool
+		level     glint.Level
+	}{
+		{glint.ColorModeNever, false, glint.LevelNone},
+		{glint.ColorMode16, true, glint.Level16},
+		{glint.ColorMode256, true, glint.Level256},
+		{glint.ColorModeTrue, true, glint.LevelTrue},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mode.String(), func(t *testing.T) {
+			test.mode.Apply()
+			if glint.ColorSupport() != test.supported {
+				t.Errorf("ColorSupport() after %v should be %v", test.mode, test.supported)
+			}
+			if level := glint.ColorLevel(); level != test.level {
+				t.Errorf("ColorLevel() after %v should be %v, got %v", test.mode, test.level, level)
+			}
+		})
+	}
+
+	t.Run("always", func(t *testing.T) {
+		glint.ColorModeAlways.Apply()
+		if !glint.ColorSupport() || glint.ColorLevel() < glint.Level16 {
+			t.Errorf("ColorModeAlways should enable at least 16 colors, got %v", glint.ColorLevel())
+		}
+	})
+
+	t.Run("NoColorPrecedence", func(t *testing.T) {
+		os.Setenv("NO_COLOR", "1")
+		core.ClearCache()
+
+		for _, mode := range []glint.ColorMode{glint.ColorModeAlways, glint.ColorMode256} {
+			mode.Apply()
+			if glint.ColorSupport() || glint.ColorLevel() != glint.LevelNone {
+				t.Errorf("NO_COLOR should take precedence over %v", mode)
+			}
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// writeConfig writes a config file to a temporary XDG_CONFIG_HOME and clears the other detection variables
+func writeConfig(t *testing.T, content string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "glint"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "glint", "config"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"TERM", "COLORTERM", "NO_COLOR", "FORCE_COLOR", "COLOR_16", "COLOR_256", "COLOR_24",
+		"TERM_PROGRAM", "WT_SESSION", "WT_PROFILE_ID", "ANSICON", "ConEmuANSI", "CI", "SSH_CONNECTION", "WSLENV", "TERMUX_VERSION"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	core.ClearCache()
+	t.Cleanup(core.ClearCache)
+}
+
+// TestConfigFile tests the precedence of the config file settings
+func TestConfigFile(t *testing.T) {
+	defer glint.SetAppName("")
+
+	const content = `
+# global settings
+level = 256
+term.xterm-kitty = truecolor
+
+[mytool]
+mode = "never"   # no color for mytool
+
+[other]
+mode = always
+term.screen = 16
+`
+
+	tests := []struct {
+		name  string
+		app   string
+		env   map[string]string
+		level core.Level
+		rule  string
+	}{
+		{"Global", "", nil, core.Level256, "config level=256"},
+		{"GlobalTerm", "", map[string]string{"TERM": "xterm-kitty"}, core.LevelTrue, "config term.xterm-kitty=truecolor"},
+		{"App", "mytool", nil, core.LevelNone, "config [mytool] mode=never"},
+		{"AppTerm", "other", map[string]string{"TERM": "screen"}, core.Level16, "config [other] term.screen=16"},
+		{"AppAlways", "other", map[string]string{"COLORTERM": "truecolor"}, core.LevelTrue, "config [other] mode=always"},
+		{"UnknownApp", "unknown", nil, core.Level256, "config level=256"},
+		{"EnvWins", "mytool", map[string]string{"COLOR_24": "1"}, core.LevelTrue, "COLOR_24"},
+		{"NoColorWins", "", map[string]string{"NO_COLOR": "1"}, core.LevelNone, "NO_COLOR"},
+		{"AppEnvWins", "mytool", map[string]string{"MYTOOL_COLOR": "256"}, core.Level256, "MYTOOL_COLOR=256"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writeConfig(t, content)
+			t.Setenv("MYTOOL_COLOR", "")
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+			glint.SetAppName(test.app)
+
+			level, rule := core.ExplainColorLevel()
+			if level != test.level || rule != test.rule {
+				t.Errorf("ExplainColorLevel() should give %v by %q, got %v by %q", test.level, test.rule, level, rule)
+			}
+		})
+	}
+}
+
+// TestConfigFileMissing tests that detection falls back to the heuristics without a config file
+func TestConfigFileMissing(t *testing.T) {
+	writeConfig(t, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("TERM", "xterm-256color")
+	core.ClearCache()
+
+	if level, rule := core.ExplainColorLevel(); level != core.Level256 || rule != "TERM=xterm-256color" {
+		t.Errorf("ExplainColorLevel() should fall back to TERM, got %v by %q", level, rule)
+	}
+	if path := glint.ConfigPath(); filepath.Base(filepath.Dir(path)) != "glint" {
+		t.Errorf("ConfigPath() should point into a glint directory, got %q", path)
+	}
+}
+
+// TestExplainForced tests that Explain reports forced settings
+func TestExplainForced(t *testing.T) {
+	defer glint.ResetColor()
+	t.Setenv("NO_COLOR", "")
+	core.ClearCache()
+
+	glint.ForceLevel(glint.Level256)
+	if e := glint.Explain(); !e.Supported || e.Level != glint.Level256 || e.Rule != "ForceLevel" {
+		t.Errorf("Explain() after ForceLevel should report it, got %+v", e)
+	}
+
+	glint.ForceColor(false)
+	if e := glint.Explain(); e.Supported || e.Level != glint.LevelNone || e.Rule != "ForceColor" {
+		t.Errorf("Explain() after ForceColor(false) should report it, got %+v", e)
+	}
+}
@@ -0,0 +1,49 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// TestLevelFromContext tests per-context color levels
+func TestLevelFromContext(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	core.ClearCache()
+	defer glint.ResetColor()
+
+	glint.ForceLevel(glint.Level16)
+
+	ctx := context.Background()
+	if level := glint.LevelFromContext(ctx); level != glint.Level16 {
+		t.Errorf("LevelFromContext without a level should fall back to ColorLevel, got %v", level)
+	}
+
+	if _, ok := glint.ContextLevel(ctx); ok {
+		t.Error("ContextLevel without a level should report none")
+	}
+
+	plain := glint.WithLevel(ctx, glint.LevelNone)
+	if level, ok := glint.ContextLevel(plain); !ok || level != glint.LevelNone {
+		t.Errorf("ContextLevel should report LevelNone as set, got %v, %v", level, ok)
+	}
+	rich, cancel := context.WithCancel(glint.WithLevel(ctx, glint.LevelTrue))
+	defer cancel()
+
+	if level := glint.LevelFromContext(plain); level != glint.LevelNone {
+		t.Errorf("LevelFromContext should return LevelNone, got %v", level)
+	}
+	if level := glint.LevelFromContext(rich); level != glint.LevelTrue {
+		t.Errorf("LevelFromContext should find the level on a parent context, got %v", level)
+	}
+	if level := glint.LevelFromContext(glint.WithLevel(rich, glint.Level256)); level != glint.Level256 {
+		t.Errorf("The innermost WithLevel should win, got %v", level)
+	}
+
+	glint.ForceLevel(glint.Level256)
+	if level := glint.LevelFromContext(rich); level != glint.LevelTrue {
+		t.Errorf("Global changes should not affect a context level, got %v", level)
+	}
+}
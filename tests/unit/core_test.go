@@ -488,3 +488,28 @@ func TestTerminalColorLevel(t *testing.T) {
 		}
 	})
 }
+
+// TestWindowsTerminalPrecedence tests that Windows Terminal is recognized before COLORTERM and TERM, which WSL and
+// remote shells set without knowing the terminal, but not before TERM=dumb
+func TestWindowsTerminalPrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   []string
+		level core.Level
+		rule  string
+	}{
+		{"WSL", []string{"TERM=xterm-256color", "WT_SESSION=1", "WSLENV=WT_SESSION:"}, core.LevelTrue, "WT_SESSION"},
+		{"ColorTerm", []string{"COLORTERM=256color", "WT_PROFILE_ID=1"}, core.LevelTrue, "WT_PROFILE_ID"},
+		{"Xterm", []string{"TERM=xterm", "WT_SESSION=1"}, core.LevelTrue, "WT_SESSION"},
+		{"Dumb", []string{"TERM=dumb", "WT_SESSION=1"}, core.LevelNone, "TERM=dumb"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			level, rule := core.ExplainColorLevelEnv(append(test.env, "XDG_CONFIG_HOME="+t.TempDir()))
+			if level != test.level || rule != test.rule {
+				t.Errorf("%v should give %v by %q, got %v by %q", test.env, test.level, test.rule, level, rule)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/glinttest"
+)
+
+// TestDumbTermPrecedence tests that TERM=dumb wins over the other terminal heuristics but not over explicit settings
+func TestDumbTermPrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   []string
+		level glint.Level
+		rule  string
+	}{
+		{"ColorTerm", []string{"TERM=dumb", "COLORTERM=truecolor"}, glint.LevelNone, "TERM=dumb"},
+		{"WindowsTerminal", []string{"TERM=dumb", "WT_SESSION=1"}, glint.LevelNone, "TERM=dumb"},
+		{"ForceColor", []string{"TERM=dumb", "FORCE_COLOR=1"}, glint.LevelTrue, "FORCE_COLOR"},
+		{"Color256", []string{"TERM=dumb", "COLOR_256=1"}, glint.Level256, "COLOR_256"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := glint.DetectWithEnv(append(test.env, "XDG_CONFIG_HOME="+t.TempDir()))
+			if e.Level != test.level || e.Rule != test.rule {
+				t.Errorf("%v should give %v by %q, got %v by %q", test.env, test.level, test.rule, e.Level, e.Rule)
+			}
+		})
+	}
+}
+
+// TestDetectEmbedding tests recognizing editors that host the terminal
+func TestDetectEmbedding(t *testing.T) {
+	tests := []struct {
+		env       map[string]string
+		embedding glint.Embedding
+		level     glint.Level
+	}{
+		{map[string]string{"INSIDE_EMACS": "29.1,comint", "TERM": "dumb"}, glint.EmbeddingEmacsShell, glint.Level16},
+		{map[string]string{"INSIDE_EMACS": "29.1,comint", "TERM": "dumb", "NO_COLOR": "1"}, glint.EmbeddingEmacsShell, glint.LevelNone},
+		{map[string]string{"INSIDE_EMACS": "29.1,term:0.96", "TERM": "eterm-color"}, glint.EmbeddingEmacsTerm, glint.Level16},
+		{map[string]string{"INSIDE_EMACS": "vterm", "TERM": "xterm-256color"}, glint.EmbeddingEmacsVterm, glint.Level256},
+		{map[string]string{"INSIDE_EMACS": "29.1,eat", "TERM": "eat-256color"}, glint.EmbeddingEmacsEat, glint.Level256},
+		{map[string]string{"INSIDE_EMACS": "29.1,tramp:2.6.2", "COLORTERM": "truecolor"}, glint.EmbeddingEmacsTramp, glint.LevelNone},
+		{map[string]string{"INSIDE_EMACS": "t"}, glint.EmbeddingEmacs, glint.Level16},
+		{map[string]string{"VIM_TERMINAL": "901", "TERM": "xterm-256color"}, glint.EmbeddingVim, glint.Level256},
+		{map[string]string{"NVIM": "/tmp/nvim.1/0", "VIM_TERMINAL": "901", "TERM": "xterm"}, glint.EmbeddingNeovim, glint.Level16},
+		{map[string]string{"TERM": "xterm-256color"}, glint.EmbeddingNone, glint.Level256},
+	}
+
+	for _, test := range tests {
+		glinttest.Terminal(t, glinttest.Options{Env: test.env, TTY: true})
+		if embedding := glint.DetectEmbedding(); embedding != test.embedding {
+			t.Errorf("DetectEmbedding() with %v should be %q, got %q", test.env, test.embedding, embedding)
+		}
+
+		// The fake terminal stands in for the heuristics, so evaluate the same environment for real
+		var env []string
+		for key, value := range test.env {
+			env = append(env, key+"="+value)
+		}
+		if e := glint.DetectWithEnv(append(env, "XDG_CONFIG_HOME="+t.TempDir())); e.Level != test.level {
+			t.Errorf("%v should give %v, got %v by %q", test.env, test.level, e.Level, e.Rule)
+		}
+	}
+}
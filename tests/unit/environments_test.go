@@ -0,0 +1,51 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// TestKnownEnvironments tests detection in the real-world environments glint ships
+func TestKnownEnvironments(t *testing.T) {
+	config := "XDG_CONFIG_HOME=" + t.TempDir()
+
+	for _, e := range glint.KnownEnvironments() {
+		t.Run(e.Name, func(t *testing.T) {
+			env := []string{config}
+			for key, value := range e.Env {
+				env = append(env, key+"="+value)
+			}
+			getenv := func(key string) string {
+				return e.Env[key]
+			}
+
+			if d := glint.DetectWithEnv(env); d.Level != e.Detected {
+				t.Errorf("DetectWithEnv should give %v, got %v by %q", e.Detected, d.Level, d.Rule)
+			}
+			if (e.Gap != "") != (e.Detected != e.Level) {
+				t.Errorf("Only gaps should detect another level than %v, got %v with gap %q", e.Level, e.Detected, e.Gap)
+			}
+			if e.Level == glint.LevelNone && e.Capabilities != (glint.Capabilities{}) {
+				t.Errorf("Terminals without escape sequences should have no capabilities, got %+v", e.Capabilities)
+			}
+			if multiplexer := core.DetectMultiplexer(getenv); multiplexer != e.Multiplexer {
+				t.Errorf("DetectMultiplexer should be %q, got %q", e.Multiplexer, multiplexer)
+			}
+			if ci := core.DetectCI(getenv); ci != e.CI {
+				t.Errorf("DetectCI should be %q, got %q", e.CI, ci)
+			}
+			if embedding := glint.Embedding(core.DetectEmbedding(getenv)); embedding != e.Embedding {
+				t.Errorf("DetectEmbedding should be %q, got %q", e.Embedding, embedding)
+			}
+		})
+	}
+
+	t.Run("Copy", func(t *testing.T) {
+		glint.KnownEnvironments()[0].Env["NO_COLOR"] = "1"
+		if _, ok := glint.KnownEnvironments()[0].Env["NO_COLOR"]; ok {
+			t.Errorf("KnownEnvironments should return a new copy on each call")
+		}
+	})
+}
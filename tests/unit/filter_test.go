@@ -0,0 +1,72 @@
+package unit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/droqsic/glint"
+)
+
+// TestDownsample tests rewriting colors for lower levels
+func TestDownsample(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		level    glint.Level
+		expected string
+	}{
+		{"TrueKeepsRGB", "\x1b[38;2;255;0;0mred", glint.LevelTrue, "\x1b[38;2;255;0;0mred"},
+		{"RGBTo256", "\x1b[1;38;2;255;0;0mred", glint.Level256, "\x1b[1;38;5;196mred"},
+		{"RGBTo16", "\x1b[48;2;0;0;238mblue", glint.Level16, "\x1b[44mblue"},
+		{"ColonRGBTo256", "\x1b[38:2::255:0:0mred", glint.Level256, "\x1b[38;5;196mred"},
+		{"256To16", "\x1b[38;5;9mred", glint.Level16, "\x1b[91mred"},
+		{"BasicKept", "\x1b[31;42mx", glint.Level16, "\x1b[31;42mx"},
+		{"NoneKeepsAttributes", "\x1b[1;31;48;5;20mx\x1b[0m", glint.LevelNone, "\x1b[1mx\x1b[0m"},
+		{"NoneDropsEmptySequence", "\x1b[38;5;20mx\x1b[39m", glint.LevelNone, "x\x1b[39m"},
+		{"UnderlineColorDroppedAt16", "\x1b[4;58;5;196mx", glint.Level16, "\x1b[4mx"},
+		{"UnderlineColorTo256", "\x1b[58;2;255;0;0mx", glint.Level256, "\x1b[58;5;196mx"},
+		{"LeadingZeroBackground", "\x1b[048;5;196mx", glint.Level16, "\x1b[101mx"},
+		{"LeadingZeroBackgroundAtTrue", "\x1b[048;5;196mx", glint.LevelTrue, "\x1b[48;5;196mx"},
+		{"LeadingZeroColonBackground", "\x1b[048:5:196mx", glint.Level16, "\x1b[101mx"},
+		{"LeadingZeroUnderline", "\x1b[058;2;255;0;0mx", glint.Level256, "\x1b[58;5;196mx"},
+		{"ResetKept", "\x1b[mx", glint.LevelNone, "\x1b[mx"},
+		{"OtherSequencesKept", "\x1b[2J\x1b]8;;http://x\x1b\\x", glint.LevelNone, "\x1b[2J\x1b]8;;http://x\x1b\\x"},
+		{"Unterminated", "x\x1b[38;2", glint.Level16, "x\x1b[38;2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := glint.Downsample(test.input, test.level); got != test.expected {
+				t.Errorf("Downsample(%q, %v) should give %q, got %q", test.input, test.level, test.expected, got)
+			}
+
+			var out bytes.Buffer
+			if err := glint.ToLevel(iotest.OneByteReader(strings.NewReader(test.input)), &out, test.level); err != nil {
+				t.Fatalf("ToLevel returned error: %v", err)
+			}
+			if out.String() != test.expected {
+				t.Errorf("ToLevel should match Downsample with split input, got %q", out.String())
+			}
+		})
+	}
+}
+
+// TestStrip tests removing escape sequences
+func TestStrip(t *testing.T) {
+	input := "\x1b[1;31merror\x1b[0m: \x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\ 日本\n"
+	expected := "error: link 日本\n"
+
+	if got := glint.Strip(input); got != expected {
+		t.Errorf("Strip should give %q, got %q", expected, got)
+	}
+
+	var out bytes.Buffer
+	if err := glint.ToPlain(iotest.OneByteReader(strings.NewReader(input)), &out); err != nil {
+		t.Fatalf("ToPlain returned error: %v", err)
+	}
+	if out.String() != expected {
+		t.Errorf("ToPlain should give %q, got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,185 @@
+package unit
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/glinttest"
+)
+
+// TestGlinttestTerminal tests detection against the fake terminal
+func TestGlinttestTerminal(t *testing.T) {
+	t.Run("Level", func(t *testing.T) {
+		glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, TTY: true})
+
+		if !glint.ColorSupport() || glint.ColorLevel() != glint.Level256 {
+			t.Errorf("ColorLevel() should be 256 colors, got %v", glint.ColorLevel())
+		}
+		if level := glint.ColorLevelFor(&bytes.Buffer{}); level != glint.Level256 {
+			t.Errorf("ColorLevelFor should treat every writer as the fake terminal, got %v", level)
+		}
+		if e := glint.Explain(); e.Rule != "fake terminal" {
+			t.Errorf("Explain() should name the fake terminal, got %q", e.Rule)
+		}
+	})
+
+	t.Run("NotTerminal", func(t *testing.T) {
+		glinttest.Terminal(t, glinttest.Options{Level: glint.LevelTrue})
+
+		if glint.ColorSupport() {
+			t.Errorf("ColorSupport() should be false without a terminal")
+		}
+		if _, _, err := glint.TerminalSize(); err == nil {
+			t.Errorf("TerminalSize() should fail without a terminal")
+		}
+	})
+
+	t.Run("Env", func(t *testing.T) {
+		glinttest.Terminal(t, glinttest.Options{Level: glint.LevelTrue, TTY: true, Env: map[string]string{"NO_COLOR": "1"}})
+
+		if glint.ColorSupport() {
+			t.Errorf("NO_COLOR from the fake environment should disable color")
+		}
+		glint.ForceColor(true)
+		if glint.ColorSupport() {
+			t.Errorf("ForceColor should respect NO_COLOR from the fake environment")
+		}
+	})
+
+	t.Run("SizeAndBackground", func(t *testing.T) {
+		glinttest.Terminal(t, glinttest.Options{TTY: true, Size: glinttest.Size{Columns: 132, Rows: 50}, Background: glint.BackgroundLight})
+
+		if columns, rows, err := glint.TerminalSize(); err != nil || columns != 132 || rows != 50 {
+			t.Errorf("TerminalSize() should be 132x50, got %dx%d (%v)", columns, rows, err)
+		}
+		if glint.DetectBackground() != glint.BackgroundLight {
+			t.Errorf("DetectBackground() should report the fake background")
+		}
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		t.Run("Inner", func(t *testing.T) {
+			glinttest.Terminal(t, glinttest.Options{Level: glint.Level16, TTY: true})
+			glint.ForceLevel(glint.LevelTrue)
+		})
+
+		glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, TTY: true})
+		t.Run("Nested", func(t *testing.T) {
+			glinttest.Terminal(t, glinttest.Options{})
+		})
+		if e := glint.Explain(); e.Level != glint.Level256 || e.Rule != "fake terminal" {
+			t.Errorf("the outer terminal should be restored without forced settings, got %v (%s)", e.Level, e.Rule)
+		}
+	})
+}
+
+// TestGlinttestVisible tests making escape sequences readable
+func TestGlinttestVisible(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain", "plain"},
+		{"\x1b[31merror\x1b[0m", "<red>error<reset>"},
+		{"\x1b[1;91;44mx\x1b[m", "<bold bright-red bg-blue>x<reset>"},
+		{"\x1b[38;5;196;48;2;0;135;255mx", "<196 bg-#0087ff>x"},
+		{"\x1b[38:2::255:135:0;4:3;58:5:12mx", "<#ff8700 underline ul-12>x"},
+		{"\x1b[22;39;49m", "</bold /fg /bg>"},
+		{"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "<link=https://example.com>link</link>"},
+		{"\x1b[2J\x1b[H", `\x1b[2J\x1b[H`},
+		{"\x1b[31", `\x1b[31`},
+	}
+
+	for _, test := range tests {
+		if got := glinttest.Visible(test.input); got != test.expected {
+			t.Errorf("Visible(%q) should be %q, got %q", test.input, test.expected, got)
+		}
+	}
+
+	glinttest.AssertOutput(t, "\x1b[1;31mfail\x1b[0m\n", "<bold red>fail<reset>\n")
+}
+
+// TestGlinttestAnnotate tests rendering output in the annotated form
+func TestGlinttestAnnotate(t *testing.T) {
+	tests := []struct {
+		input    string
+		level    glint.Level
+		expected string
+	}{
+		{"plain", glint.LevelTrue, "plain"},
+		{"\x1b[1;38;2;255;0;0mfail\x1b[0m ok", glint.LevelTrue, "[fg=#ff0000 bold]fail[/] ok"},
+		{"\x1b[1;38;2;255;0;0mfail\x1b[0m ok", glint.Level256, "[fg=196 bold]fail[/] ok"},
+		{"\x1b[1;38;2;255;0;0mfail\x1b[0m ok", glint.Level16, "[fg=bright-red bold]fail[/] ok"},
+		{"\x1b[1;38;2;255;0;0mfail\x1b[0m ok", glint.LevelNone, "[bold]fail[/] ok"},
+		{"\x1b[31;44ma\x1b[4mb\x1b[24;39mc", glint.LevelTrue, "[fg=red bg=blue]a[/][fg=red bg=blue underline]b[/][bg=blue]c[/]"},
+		{"\x1b[32mone\ntwo\n\nthree\x1b[0m\n", glint.LevelTrue, "[fg=green]one[/]\n[fg=green]two[/]\n\n[fg=green]three[/]\n"},
+		{"\x1b]8;;https://example.com\x1b\\docs\x1b]8;;\x1b\\", glint.LevelTrue, "[link=https://example.com]docs[/]"},
+		{"\x1b[2Jtop", glint.LevelTrue, `\x1b[2Jtop`},
+	}
+
+	for _, test := range tests {
+		if got := glinttest.Annotate(test.input, test.level); got != test.expected {
+			t.Errorf("Annotate(%q, %v) should be %q, got %q", test.input, test.level, test.expected, got)
+		}
+	}
+}
+
+// recordingT records the errors reported by a helper under test
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+// Errorf records the error instead of failing the test
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// TestGlinttestUpdateFlag tests that the golden file flag of glinttest leaves -update to the package under test
+func TestGlinttestUpdateFlag(t *testing.T) {
+	golden := flag.Lookup("glinttest.update")
+	if golden == nil {
+		t.Fatal("glinttest should register the -glinttest.update flag")
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	update := fs.Bool("update", false, "update the golden files of this package")
+
+	before := golden.Value.String()
+	if err := fs.Parse([]string{"-update"}); err != nil || !*update {
+		t.Fatalf("A package flag named -update should be usable next to glinttest, got %v", err)
+	}
+	if golden.Value.String() != before {
+		t.Errorf("-update should not change -glinttest.update, got %s", golden.Value.String())
+	}
+}
+
+// TestGlinttestGolden tests comparing output against a golden file
+func TestGlinttestGolden(t *testing.T) {
+	output := "\x1b[1mglint\x1b[0m 1.0\n\x1b[38;2;255;0;0merror\x1b[0m: disk full\n"
+	glinttest.AssertGolden(t, "glinttest_banner", output, glint.LevelTrue)
+	if flag.Lookup("glinttest.update").Value.String() == "true" {
+		return
+	}
+
+	r := &recordingT{TB: t}
+	glinttest.AssertGolden(r, "glinttest_banner", strings.Replace(output, "full", "empty", 1), glint.LevelTrue)
+	if len(r.errors) != 1 {
+		t.Fatalf("AssertGolden should report one error for different output, got %d", len(r.errors))
+	}
+
+	diff := "  want                            | got\n" +
+		"  [bold]glint[/] 1.0              | [bold]glint[/] 1.0\n" +
+		"! [fg=#ff0000]error[/]: disk full | [fg=#ff0000]error[/]: disk empty\n" +
+		"                                  | "
+	if !strings.HasSuffix(r.errors[0], diff) {
+		t.Errorf("AssertGolden should show a side-by-side diff, got\n%s", r.errors[0])
+	}
+}
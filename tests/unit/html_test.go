@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/droqsic/glint"
+)
+
+// toHTML converts input with ToHTML and fails the test on error
+func toHTML(t *testing.T, input string, opts glint.HTMLOptions) string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := glint.ToHTML(strings.NewReader(input), &out, opts); err != nil {
+		t.Fatalf("ToHTML(%q) returned error: %v", input, err)
+	}
+	return out.String()
+}
+
+// TestToHTML tests the ToHTML function with inline styles
+func TestToHTML(t *testing.T) {
+	fragment := glint.HTMLOptions{Fragment: true}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Plain", "hello", "hello"},
+		{"Escaping", "<b>&\"", "&lt;b&gt;&amp;&#34;"},
+		{"BasicColor", "\x1b[31mred\x1b[0m", `<span style="color: #cd0000;">red</span>`},
+		{"BrightBackground", "\x1b[104mx\x1b[0m", `<span style="background-color: #5c5cff;">x</span>`},
+		{"Color256", "\x1b[38;5;208mx", `<span style="color: #ff8700;">x</span>`},
+		{"TrueColor", "\x1b[38;2;1;2;3mx", `<span style="color: #010203;">x</span>`},
+		{"Attributes", "\x1b[1;3;4;9mx", `<span style="font-weight: bold; font-style: italic; text-decoration-line: underline line-through;">x</span>`},
+		{"Reverse", "\x1b[7mx", `<span style="color: #000000; background-color: #e5e5e5;">x</span>`},
+		{"StyleChange", "\x1b[31ma\x1b[32mb\x1b[0mc", `<span style="color: #cd0000;">a</span><span style="color: #00cd00;">b</span>c`},
+		{"DropsOtherSequences", "a\x1b[2Kb\x1b]0;title\x07c", "abc"},
+		{"Hyperlink", "\x1b]8;;https://example.com/?a=1&b=2\x1b\\link\x1b]8;;\x1b\\ after", `<a href="https://example.com/?a=1&amp;b=2">link</a> after`},
+		{"UnsafeHyperlink", "\x1b]8;;javascript:alert(1)\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"ColoredHyperlink", "\x1b[32m\x1b]8;;https://x.dev\x1b\\go\x1b]8;;\x1b\\\x1b[0m", `<a href="https://x.dev"><span style="color: #00cd00;">go</span></a>`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := toHTML(t, test.input, fragment); result != test.expected {
+				t.Errorf("ToHTML(%q) should return %q, got %q", test.input, test.expected, result)
+			}
+		})
+	}
+
+	t.Run("PreElement", func(t *testing.T) {
+		result := toHTML(t, "x", glint.HTMLOptions{})
+		expected := `<pre style="color: #e5e5e5; background-color: #000000;">x</pre>`
+		if result != expected {
+			t.Errorf("ToHTML should wrap output in a pre element, got %q", result)
+		}
+	})
+
+	t.Run("CustomPalette", func(t *testing.T) {
+		palette := glint.PaletteXterm
+		palette.Colors[1] = "#dc322f"
+		result := toHTML(t, "\x1b[31mx", glint.HTMLOptions{Fragment: true, Palette: palette})
+		if result != `<span style="color: #dc322f;">x</span>` {
+			t.Errorf("ToHTML should map basic colors through the palette, got %q", result)
+		}
+	})
+
+	t.Run("SplitReads", func(t *testing.T) {
+		input := "\x1b[38;2;255;0;0m日本\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\"
+		var out bytes.Buffer
+		if err := glint.ToHTML(iotest.OneByteReader(strings.NewReader(input)), &out, fragment); err != nil {
+			t.Fatalf("ToHTML returned error: %v", err)
+		}
+		if out.String() != toHTML(t, input, fragment) {
+			t.Errorf("ToHTML should produce the same output for split reads, got %q", out.String())
+		}
+	})
+}
+
+// TestToHTMLClasses tests the ToHTML function with CSS classes
+func TestToHTMLClasses(t *testing.T) {
+	opts := glint.HTMLOptions{Classes: true, ClassPrefix: "t-", Fragment: true}
+
+	result := toHTML(t, "\x1b[1;31ma\x1b[38;5;208mb", opts)
+	expected := `<span class="t-fg-1 t-bold">a</span><span class="t-bold" style="color: #ff8700;">b</span>`
+	if result != expected {
+		t.Errorf("ToHTML with classes should return %q, got %q", expected, result)
+	}
+
+	css := glint.HTMLStylesheet(opts)
+	for _, rule := range []string{".t-fg-1 { color: #cd0000; }", ".t-bold { font-weight: bold; }", "pre.t-term"} {
+		if !strings.Contains(css, rule) {
+			t.Errorf("HTMLStylesheet should contain %q, got:\n%s", rule, css)
+		}
+	}
+}
+
+// TestToHTMLClassPrefix tests that ToHTML and HTMLStylesheet ignore a class prefix that is not a plain identifier
+func TestToHTMLClassPrefix(t *testing.T) {
+	opts := glint.HTMLOptions{Classes: true, ClassPrefix: `x"><script>alert(1)</script><i class="`}
+
+	result := toHTML(t, "\x1b[31ma", opts)
+	expected := `<pre class="glint-term"><span class="glint-fg-1">a</span></pre>`
+	if result != expected {
+		t.Errorf("ToHTML with a hostile prefix should return %q, got %q", expected, result)
+	}
+
+	css := glint.HTMLStylesheet(opts)
+	if strings.Contains(css, "<") || !strings.Contains(css, ".glint-fg-1 {") {
+		t.Errorf("HTMLStylesheet with a hostile prefix should use the default prefix, got:\n%s", css)
+	}
+}
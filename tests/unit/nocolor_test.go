@@ -0,0 +1,91 @@
+package unit
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/glinttest"
+	"github.com/droqsic/glint/slogcolor"
+)
+
+// TestNoColorPolicy tests keeping text attributes under NO_COLOR
+func TestNoColorPolicy(t *testing.T) {
+	t.Cleanup(func() { glint.SetNoColorPolicy(glint.NoColorStripAll) })
+	noColor := map[string]string{"NO_COLOR": "1"}
+
+	t.Run("StripAll", func(t *testing.T) {
+		glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, TTY: true, Env: noColor})
+		glint.SetNoColorPolicy(glint.NoColorStripAll)
+
+		if glint.ColorSupport() || glint.AttributeSupport() {
+			t.Errorf("NO_COLOR should disable colors and attributes by default")
+		}
+	})
+
+	t.Run("ColorOnly", func(t *testing.T) {
+		glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, TTY: true, Env: noColor})
+		glint.SetNoColorPolicy(glint.NoColorColorOnly)
+
+		if glint.ColorSupport() || glint.ColorLevel() != glint.LevelNone {
+			t.Errorf("NO_COLOR should still disable colors, got %v", glint.ColorLevel())
+		}
+		if !glint.AttributeSupport() || !glint.AttributeSupportFor(&bytes.Buffer{}) {
+			t.Errorf("NoColorColorOnly should keep attributes under NO_COLOR")
+		}
+		if e := glint.Explain(); e.Supported || !e.Attributes || e.Rule != "NO_COLOR" {
+			t.Errorf("Explain() should report attributes without colors, got %+v", e)
+		}
+		if e := glint.DetectWithEnv([]string{"NO_COLOR=1"}); !e.Attributes {
+			t.Errorf("DetectWithEnv should apply the policy, got %+v", e)
+		}
+
+		var buf bytes.Buffer
+		slog.New(slogcolor.NewHandler(&buf, nil)).Error("failed", "code", 3)
+		out := buf.String()
+		if !strings.Contains(out, "\x1b[1mERR\x1b[0m") || strings.Contains(out, "\x1b[3") {
+			t.Errorf("slogcolor should keep the bold badge without colors, got %q", out)
+		}
+	})
+
+	t.Run("Forced", func(t *testing.T) {
+		glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, TTY: true, Env: noColor})
+		glint.SetNoColorPolicy(glint.NoColorColorOnly)
+
+		glint.ForceColor(true)
+		if glint.ColorSupport() || !glint.AttributeSupport() {
+			t.Errorf("ForceColor(true) under NO_COLOR should keep attributes only")
+		}
+		glint.ForceLevel(glint.LevelTrue)
+		if glint.ColorSupport() || !glint.AttributeSupport() {
+			t.Errorf("ForceLevel under NO_COLOR should keep attributes only")
+		}
+		glint.ForceColor(false)
+		if glint.AttributeSupport() {
+			t.Errorf("ForceColor(false) should disable attributes")
+		}
+	})
+
+	t.Run("OtherRules", func(t *testing.T) {
+		glint.SetNoColorPolicy(glint.NoColorColorOnly)
+
+		glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, Env: noColor})
+		if glint.AttributeSupport() {
+			t.Errorf("Output that is not a terminal should not get attributes")
+		}
+
+		glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, TTY: true, Env: map[string]string{"NO_COLOR": "1", "POLICYTEST_COLOR": "never"}})
+		glint.SetAppName("policytest")
+		t.Cleanup(func() { glint.SetAppName("") })
+		if glint.AttributeSupport() {
+			t.Errorf("POLICYTEST_COLOR=never should disable attributes")
+		}
+
+		glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, TTY: true})
+		if !glint.AttributeSupport() || !glint.ColorSupport() {
+			t.Errorf("Attributes should follow color support without NO_COLOR")
+		}
+	})
+}
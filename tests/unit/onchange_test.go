@@ -0,0 +1,202 @@
+package unit
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// TestOnChange tests that listeners are notified of level transitions
+func TestOnChange(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	core.ClearCache()
+	defer glint.ResetColor()
+
+	glint.ForceLevel(glint.Level16)
+
+	type change struct{ old, new glint.Level }
+	var changes []change
+	unsubscribe := glint.OnChange(func(old, new glint.Level) {
+		changes = append(changes, change{old, new})
+	})
+
+	glint.ForceLevel(glint.Level256)
+	glint.ForceLevel(glint.Level256)
+	glint.ForceColor(false)
+	glint.Refresh()
+
+	expected := []change{{glint.Level16, glint.Level256}, {glint.Level256, glint.LevelNone}}
+	if len(changes) != len(expected) {
+		t.Fatalf("OnChange should report %v, got %v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Change %d should be %v, got %v", i, expected[i], changes[i])
+		}
+	}
+
+	unsubscribe()
+	unsubscribe()
+	glint.ForceLevel(glint.LevelTrue)
+	if len(changes) != len(expected) {
+		t.Errorf("Unsubscribed listener should not be called, got %v", changes)
+	}
+}
+
+// TestOnChangeReentrant tests that listeners may call back into glint
+func TestOnChangeReentrant(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	core.ClearCache()
+	defer glint.ResetColor()
+
+	glint.ForceLevel(glint.Level16)
+
+	var seen glint.Level
+	unsubscribe := glint.OnChange(func(_, new glint.Level) {
+		seen = glint.ColorLevel()
+		if new == glint.LevelTrue {
+			glint.ForceLevel(glint.Level256)
+		}
+	})
+	defer unsubscribe()
+
+	glint.ForceLevel(glint.LevelTrue)
+	if seen != glint.Level256 || glint.ColorLevel() != glint.Level256 {
+		t.Errorf("Listener should see the new state and be able to change it, saw %v, now %v", seen, glint.ColorLevel())
+	}
+}
+
+// TestOnChangeConcurrent tests subscribing and transitions from many goroutines
+func TestOnChangeConcurrent(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	core.ClearCache()
+	defer glint.ResetColor()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				unsubscribe := glint.OnChange(func(old, new glint.Level) {
+					if old == new {
+						t.Error("OnChange should not report unchanged levels")
+					}
+				})
+				if i%2 == 0 {
+					glint.ForceLevel(glint.Level256)
+				} else {
+					glint.ForceLevel(glint.Level16)
+				}
+				unsubscribe()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestOnChangeOrder tests that concurrent transitions are delivered in order and end at the current level
+func TestOnChangeOrder(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	core.ClearCache()
+	defer glint.ResetColor()
+
+	glint.ForceLevel(glint.Level16)
+
+	type change struct{ old, new glint.Level }
+	var changes []change
+	unsubscribe := glint.OnChange(func(old, new glint.Level) {
+		changes = append(changes, change{old, new})
+	})
+	defer unsubscribe()
+
+	levels := []glint.Level{glint.Level16, glint.Level256, glint.LevelTrue, glint.LevelNone}
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				glint.ForceLevel(levels[(i+j)%len(levels)])
+			}
+		}()
+	}
+	wg.Wait()
+
+	last := glint.Level16
+	for i, c := range changes {
+		if c.old != last {
+			t.Fatalf("Change %d should start at %v, the level of the previous change, got %v", i, last, c)
+		}
+		last = c.new
+	}
+	if last != glint.ColorLevel() {
+		t.Errorf("The last change should end at the current level %v, got %v", glint.ColorLevel(), last)
+	}
+}
+
+// TestOnChangePanic tests that a panicking listener does not stop later transitions from being delivered
+func TestOnChangePanic(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	core.ClearCache()
+	defer glint.ResetColor()
+
+	glint.ForceLevel(glint.Level16)
+
+	var levels []glint.Level
+	unsubscribe := glint.OnChange(func(_, new glint.Level) {
+		levels = append(levels, new)
+		if new == glint.Level256 {
+			glint.ForceLevel(glint.LevelTrue)
+			panic("listener failed")
+		}
+	})
+	defer unsubscribe()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("The panic of a listener should propagate")
+			}
+		}()
+		glint.ForceLevel(glint.Level256)
+	}()
+	if len(levels) != 2 || levels[1] != glint.LevelTrue {
+		t.Fatalf("The transition queued before the panic should still be delivered, got %v", levels)
+	}
+
+	glint.ForceLevel(glint.LevelNone)
+	if len(levels) != 3 || levels[2] != glint.LevelNone {
+		t.Errorf("Transitions after the panic should be delivered, got %v", levels)
+	}
+}
+
+// TestOnChangeRefresh tests that Refresh reports the level of the environment before the change,
+// even if it was never computed
+func TestOnChangeRefresh(t *testing.T) {
+	defer func() {
+		glint.SetAppName("")
+		glint.ResetColor()
+	}()
+
+	t.Setenv("ONCHANGE_COLOR", "16")
+	glint.ResetColor()
+	glint.SetAppName("onchange")
+	core.SetEnvCache()
+
+	type change struct{ old, new glint.Level }
+	var changes []change
+	unsubscribe := glint.OnChange(func(old, new glint.Level) {
+		changes = append(changes, change{old, new})
+	})
+	defer unsubscribe()
+
+	t.Setenv("ONCHANGE_COLOR", "256")
+	glint.Refresh()
+
+	if len(changes) != 1 || changes[0] != (change{glint.Level16, glint.Level256}) {
+		t.Errorf("Refresh should report the change from Level16 to Level256, got %v", changes)
+	}
+}
@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/droqsic/glint/pty"
+)
+
+// TestPtyStart tests running a child under a pseudo-terminal
+func TestPtyStart(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		cmd := exec.Command("true")
+		if _, err := pty.Start(cmd, pty.Size{}); !errors.Is(err, pty.ErrUnsupported) {
+			t.Errorf("Start should return ErrUnsupported on %s, got %v", runtime.GOOS, err)
+		}
+		t.Skip("Pseudo-terminals are only supported on Linux")
+	}
+
+	cmd := exec.Command("sh", "-c", `test -t 0 && test -t 1 && echo tty; stty size; exit 7`)
+	term, err := pty.Start(cmd, pty.Size{Columns: 100, Rows: 30})
+	if err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	defer term.Close()
+
+	if size, err := term.Size(); err != nil || size != (pty.Size{Columns: 100, Rows: 30}) {
+		t.Errorf("Size() should be 100x30, got %+v (%v)", size, err)
+	}
+
+	out, err := io.ReadAll(term)
+	if err != nil {
+		t.Fatalf("Reading the output should end with EOF, got %v", err)
+	}
+	if got := strings.ReplaceAll(string(out), "\r\n", "\n"); got != "tty\n30 100\n" {
+		t.Errorf("Child should see a 100x30 terminal, got %q", got)
+	}
+
+	err = cmd.Wait()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 7 {
+		t.Errorf("Wait should report exit code 7, got %v", err)
+	}
+}
+
+// TestPtyRawOutput tests that raw output keeps plain newlines
+func TestPtyRawOutput(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Pseudo-terminals are only supported on Linux")
+	}
+
+	cmd := exec.Command("printf", `a\nb\n`)
+	term, err := pty.StartWith(cmd, pty.Options{RawOutput: true})
+	if err != nil {
+		t.Fatalf("StartWith returned error: %v", err)
+	}
+	defer term.Close()
+
+	out, _ := io.ReadAll(term)
+	cmd.Wait()
+	if string(out) != "a\nb\n" {
+		t.Errorf("RawOutput should keep plain newlines, got %q", out)
+	}
+}
@@ -0,0 +1,94 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/droqsic/glint"
+)
+
+// fakeClock returns a clock that starts at start and advances by step on every call
+func fakeClock(start time.Time, step time.Duration) func() time.Time {
+	now := start.Add(-step)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+// TestRecorder tests the asciicast Recorder
+func TestRecorder(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	t.Run("HeaderAndEvents", func(t *testing.T) {
+		var out bytes.Buffer
+		rec := glint.NewRecorder(&out, 80, 24)
+		rec.SetClock(fakeClock(start, 500*time.Millisecond))
+
+		rec.Write([]byte("\x1b[31mred\x1b[0m\n"))
+		rec.Resize(100, 30)
+		if err := rec.Close(); err != nil {
+			t.Fatalf("Close returned error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("Recorder should write a header and 2 events, got %d lines:\n%s", len(lines), out.String())
+		}
+
+		var header struct {
+			Version   int               `json:"version"`
+			Width     int               `json:"width"`
+			Height    int               `json:"height"`
+			Timestamp int64             `json:"timestamp"`
+			Env       map[string]string `json:"env"`
+		}
+		if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+			t.Fatalf("Header is not valid JSON: %v", err)
+		}
+		if header.Version != 2 || header.Width != 80 || header.Height != 24 || header.Timestamp != start.Unix() {
+			t.Errorf("Unexpected header: %+v", header)
+		}
+		if _, ok := header.Env["GLINT_COLOR_LEVEL"]; !ok {
+			t.Errorf("Header env should record the color level, got %v", header.Env)
+		}
+		if _, ok := header.Env["TERM"]; !ok {
+			t.Errorf("Header env should record TERM, got %v", header.Env)
+		}
+
+		expected := []string{`[0.500000,"o","\u001b[31mred\u001b[0m\n"]`, `[1.000000,"r","100x30"]`}
+		for i, want := range expected {
+			if lines[i+1] != want {
+				t.Errorf("Event %d should be %s, got %s", i, want, lines[i+1])
+			}
+		}
+	})
+
+	t.Run("SplitCharacter", func(t *testing.T) {
+		var out bytes.Buffer
+		rec := glint.NewRecorder(&out, 80, 24)
+		rec.SetClock(fakeClock(start, time.Second))
+
+		text := []byte("日本")
+		rec.Write(text[:4])
+		rec.Write(text[4:])
+		rec.Close()
+
+		if strings.Contains(out.String(), "\\ufffd") || !strings.Contains(out.String(), `"日"`) || !strings.Contains(out.String(), `"本"`) {
+			t.Errorf("Recorder should not split characters across events, got:\n%s", out.String())
+		}
+	})
+
+	t.Run("EmptyRecording", func(t *testing.T) {
+		var out bytes.Buffer
+		rec := glint.NewRecorder(&out, 80, 24)
+		rec.Close()
+
+		if strings.Count(out.String(), "\n") != 1 || !strings.HasPrefix(out.String(), `{"version":2`) {
+			t.Errorf("Close should write the header of an empty recording, got %q", out.String())
+		}
+	})
+}
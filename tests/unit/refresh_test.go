@@ -0,0 +1,140 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// TestRefresh tests that Refresh picks up environment changes made after the first detection
+func TestRefresh(t *testing.T) {
+	defer func() {
+		glint.SetAppName("")
+		glint.ResetColor()
+	}()
+
+	t.Setenv("REFRESH_COLOR", "256")
+	glint.ResetColor()
+	glint.SetAppName("refresh")
+
+	if level := glint.ColorLevel(); level != glint.Level256 {
+		t.Fatalf("ColorLevel() should be Level256, got %v", level)
+	}
+
+	os.Setenv("REFRESH_COLOR", "16")
+	if level := glint.ColorLevel(); level != glint.Level256 {
+		t.Errorf("ColorLevel() should stay cached until Refresh, got %v", level)
+	}
+
+	glint.Refresh()
+	if level := glint.ColorLevel(); level != glint.Level16 {
+		t.Errorf("ColorLevel() after Refresh should be Level16, got %v", level)
+	}
+
+	glint.ForceLevel(glint.LevelTrue)
+	glint.Refresh()
+	if level := glint.ColorLevel(); level != glint.LevelTrue {
+		t.Errorf("Refresh should keep forced settings, got %v", level)
+	}
+}
+
+// TestDetectWithEnv tests detection against an explicit environment snapshot
+func TestDetectWithEnv(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	core.ClearCache()
+	defer core.ClearCache()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "glint"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "glint", "config"), []byte("level = 256\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		env       []string
+		supported bool
+		level     glint.Level
+		rule      string
+	}{
+		{"IgnoresProcessEnv", []string{"TERM=xterm-256color"}, true, glint.Level256, "TERM=xterm-256color"},
+		{"NoColor", []string{"TERM=xterm-256color", "NO_COLOR=1"}, false, glint.LevelNone, "NO_COLOR"},
+		{"LaterWins", []string{"COLORTERM=256color", "COLORTERM=truecolor"}, true, glint.LevelTrue, "COLORTERM=truecolor"},
+		{"Dumb", []string{"TERM=dumb"}, false, glint.LevelNone, "TERM=dumb"},
+		{"Config", []string{"XDG_CONFIG_HOME=" + dir, "TERM=xterm"}, true, glint.Level256, "config level=256"},
+		{"Malformed", []string{"GARBAGE", "TERM=screen"}, true, glint.Level16, "TERM=screen"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hasConfigHome := slices.ContainsFunc(test.env, func(kv string) bool {
+				return strings.HasPrefix(kv, "XDG_CONFIG_HOME=")
+			})
+			if !hasConfigHome {
+				test.env = append([]string{"XDG_CONFIG_HOME=" + t.TempDir()}, test.env...)
+			}
+			e := glint.DetectWithEnv(test.env)
+			if e.Supported != test.supported || e.Level != test.level || e.Rule != test.rule {
+				t.Errorf("DetectWithEnv(%q) should give %v, %v by %q, got %+v", test.env, test.supported, test.level, test.rule, e)
+			}
+		})
+	}
+}
+
+// TestDetectWithEnvConfigDir tests that DetectWithEnv finds the config file through HOME or APPDATA of the given
+// environment and never through the process environment
+func TestDetectWithEnvConfigDir(t *testing.T) {
+	writeFile := func(dir, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(dir, "glint"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "glint", "config"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	process := t.TempDir()
+	writeFile(process, "level = truecolor\n")
+	writeFile(filepath.Join(process, ".config"), "level = truecolor\n")
+	writeFile(filepath.Join(process, "Library", "Application Support"), "level = truecolor\n")
+	t.Setenv("XDG_CONFIG_HOME", process)
+	t.Setenv("HOME", process)
+	t.Setenv("APPDATA", process)
+
+	home := t.TempDir()
+	switch runtime.GOOS {
+	case "windows":
+		writeFile(home, "level = 256\n")
+	case "darwin", "ios":
+		writeFile(filepath.Join(home, "Library", "Application Support"), "level = 256\n")
+	default:
+		writeFile(filepath.Join(home, ".config"), "level = 256\n")
+	}
+
+	tests := []struct {
+		name  string
+		env   []string
+		level glint.Level
+		rule  string
+	}{
+		{"NoConfigDir", []string{"TERM=xterm"}, glint.Level16, "TERM=xterm"},
+		{"Home", []string{"HOME=" + home, "APPDATA=" + home, "TERM=xterm"}, glint.Level256, "config level=256"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if e := glint.DetectWithEnv(test.env); e.Level != test.level || e.Rule != test.rule {
+				t.Errorf("DetectWithEnv(%q) should give %v by %q, got %+v", test.env, test.level, test.rule, e)
+			}
+		})
+	}
+}
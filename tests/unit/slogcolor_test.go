@@ -0,0 +1,167 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/slogcolor"
+)
+
+// dropTime is a ReplaceAttr function that removes the record time for stable output
+func dropTime(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.TimeKey && len(groups) == 0 {
+		return slog.Attr{}
+	}
+	return a
+}
+
+// TestSlogcolorPlain tests the slogcolor handler writing to a destination without color support
+func TestSlogcolorPlain(t *testing.T) {
+	glint.ResetColor()
+
+	tests := []struct {
+		name     string
+		log      func(l *slog.Logger)
+		expected string
+	}{
+		{"Message", func(l *slog.Logger) { l.Info("hello") }, "INF hello\n"},
+		{"Attrs", func(l *slog.Logger) { l.Warn("disk", "free", 12, "path", "/var/log", "ok", false) }, "WRN disk free=12 path=/var/log ok=false\n"},
+		{"Quoting", func(l *slog.Logger) { l.Info("x", "msg", "two words", "empty", "") }, "INF x msg=\"two words\" empty=\"\"\n"},
+		{"MessageControls", func(l *slog.Logger) { l.Info("line\nforged \x1b[31mred") }, "INF \"line\\nforged \\x1b[31mred\"\n"},
+		{"Error", func(l *slog.Logger) { l.Error("failed", "err", errors.New("boom")) }, "ERR failed err=boom\n"},
+		{"Group", func(l *slog.Logger) { l.Info("req", slog.Group("http", "method", "GET", "status", 200)) }, "INF req http.method=GET http.status=200\n"},
+		{"EmptyGroup", func(l *slog.Logger) { l.Info("req", slog.Group("http")) }, "INF req\n"},
+		{"InlineGroup", func(l *slog.Logger) { l.Info("req", slog.Group("", "a", 1)) }, "INF req a=1\n"},
+		{"WithAttrs", func(l *slog.Logger) { l.With("svc", "api").Info("up") }, "INF up svc=api\n"},
+		{"WithGroup", func(l *slog.Logger) { l.With("a", 1).WithGroup("g").With("b", 2).Info("m", "c", 3) }, "INF m a=1 g.b=2 g.c=3\n"},
+		{"LevelOffset", func(l *slog.Logger) { l.Log(context.Background(), slog.LevelWarn+2, "hot") }, "WRN+2 hot\n"},
+		{"Debug", func(l *slog.Logger) { l.Debug("hidden") }, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			test.log(slog.New(slogcolor.NewHandler(&out, &slogcolor.Options{ReplaceAttr: dropTime})))
+			if out.String() != test.expected {
+				t.Errorf("Handler should write %q, got %q", test.expected, out.String())
+			}
+		})
+	}
+}
+
+// TestSlogcolorReplaceAttr tests that ReplaceAttr receives the same groups and keys as with slog.TextHandler
+func TestSlogcolorReplaceAttr(t *testing.T) {
+	glint.ResetColor()
+
+	var seen []string
+	replace := func(groups []string, a slog.Attr) slog.Attr {
+		seen = append(seen, strings.Join(append(groups, a.Key), "."))
+		switch a.Key {
+		case slog.TimeKey:
+			return slog.Attr{}
+		case "secret":
+			return slog.String("secret", "***")
+		case "drop":
+			return slog.Attr{}
+		}
+		return a
+	}
+
+	var out bytes.Buffer
+	logger := slog.New(slogcolor.NewHandler(&out, &slogcolor.Options{ReplaceAttr: replace}))
+	logger.WithGroup("g").Info("m", "secret", "hunter2", "drop", 1, slog.Group("h", "k", "v"))
+
+	if expected := "INF m g.secret=*** g.h.k=v\n"; out.String() != expected {
+		t.Errorf("Handler should write %q, got %q", expected, out.String())
+	}
+
+	expected := []string{"time", "level", "msg", "g.secret", "g.drop", "g.h.k"}
+	if strings.Join(seen, " ") != strings.Join(expected, " ") {
+		t.Errorf("ReplaceAttr should see %v, got %v", expected, seen)
+	}
+}
+
+// TestSlogcolorColored tests the slogcolor handler when color is enabled
+func TestSlogcolorColored(t *testing.T) {
+	glint.ForceColor(true)
+	defer glint.ResetColor()
+	if !glint.ColorSupportFor(&bytes.Buffer{}) {
+		t.Skip("Color cannot be forced in this environment")
+	}
+
+	var out bytes.Buffer
+	logger := slog.New(slogcolor.NewHandler(&out, &slogcolor.Options{ReplaceAttr: dropTime}))
+	logger.Error("failed", "err", errors.New("boom"), "n", 3)
+
+	expected := "\x1b[1m\x1b[31mERR\x1b[0m failed \x1b[36merr\x1b[0m=\x1b[91mboom\x1b[0m \x1b[36mn\x1b[0m=\x1b[35m3\x1b[0m\n"
+	if out.String() != expected {
+		t.Errorf("Handler should write %q, got %q", expected, out.String())
+	}
+}
+
+// TestSlogcolorContextLevel tests that a level set with glint.WithLevel replaces the level of the destination
+func TestSlogcolorContextLevel(t *testing.T) {
+	glint.ResetColor()
+	defer glint.ResetColor()
+
+	calls := 0
+	replace := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == "svc" {
+			calls++
+		}
+		return dropTime(groups, a)
+	}
+
+	var out bytes.Buffer
+	logger := slog.New(slogcolor.NewHandler(&out, &slogcolor.Options{ReplaceAttr: replace})).With("svc", "api")
+
+	logger.InfoContext(glint.WithLevel(context.Background(), glint.Level256), "up", "n", 3)
+	expected := "\x1b[1m\x1b[32mINF\x1b[0m up \x1b[36msvc\x1b[0m=api \x1b[36mn\x1b[0m=\x1b[35m3\x1b[0m\n"
+	if out.String() != expected {
+		t.Errorf("Handler should write %q at the context level, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	logger.InfoContext(context.Background(), "up", "n", 3)
+	if expected := "INF up svc=api n=3\n"; out.String() != expected {
+		t.Errorf("Handler should write %q without a context level, got %q", expected, out.String())
+	}
+	if calls != 1 {
+		t.Errorf("ReplaceAttr should run once for an attribute added with With, ran %d times", calls)
+	}
+
+	glint.ForceColor(true)
+	if !glint.ColorSupportFor(&bytes.Buffer{}) {
+		t.Skip("Color cannot be forced in this environment")
+	}
+	out.Reset()
+	colored := slog.New(slogcolor.NewHandler(&out, &slogcolor.Options{ReplaceAttr: dropTime})).With("svc", "api")
+	colored.InfoContext(glint.WithLevel(context.Background(), glint.LevelNone), "up", "n", 3)
+	if expected := "INF up svc=api n=3\n"; out.String() != expected {
+		t.Errorf("Handler should write plain text for a context at LevelNone, got %q", out.String())
+	}
+}
+
+// TestColorSupportFor tests the ColorSupportFor function
+func TestColorSupportFor(t *testing.T) {
+	glint.ResetColor()
+	defer glint.ResetColor()
+
+	var buf bytes.Buffer
+	if glint.ColorSupportFor(&buf) {
+		t.Errorf("ColorSupportFor should return false for a buffer")
+	}
+	if level := glint.ColorLevelFor(&buf); level != glint.LevelNone {
+		t.Errorf("ColorLevelFor should return LevelNone for a buffer, got %v", level)
+	}
+
+	glint.ForceColor(false)
+	if glint.ColorSupportFor(&buf) {
+		t.Errorf("ColorSupportFor should return false after ForceColor(false)")
+	}
+}
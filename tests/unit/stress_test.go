@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// TestStateStress hammers every state transition concurrently with readers. Run with -race to check that
+// force, reset and refresh are race free. Readers check that a forced configuration is always reported whole.
+func TestStateStress(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	core.ClearCache()
+	defer glint.ResetColor()
+
+	iterations := 500
+	if testing.Short() {
+		iterations = 50
+	}
+
+	var wg sync.WaitGroup
+	writers := []func(){
+		func() { glint.ForceLevel(glint.Level256) },
+		func() { glint.ForceLevel(glint.LevelNone) },
+		func() { glint.ForceColor(false) },
+		func() { glint.ResetColor() },
+		func() { glint.Refresh() },
+	}
+	for _, write := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range iterations {
+				write()
+			}
+		}()
+	}
+
+	errs := make(chan string, 8)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range iterations {
+				e := glint.Explain()
+				if e.Supported != (e.Level != glint.LevelNone) {
+					errs <- "support and level disagree: " + e.Rule
+					return
+				}
+				if e.Rule == "ForceLevel" && e.Level != glint.Level256 && e.Level != glint.LevelNone {
+					errs <- "ForceLevel reported an unforced level"
+					return
+				}
+				glint.ColorSupport()
+				glint.ColorLevel()
+				glint.ColorLevelFor(nil)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
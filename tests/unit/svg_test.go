@@ -0,0 +1,120 @@
+package unit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/internal/core"
+)
+
+// toSVG renders input with ToSVG and fails the test on error
+func toSVG(t *testing.T, input string, opts glint.SVGOptions) string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := glint.ToSVG(strings.NewReader(input), &out, opts); err != nil {
+		t.Fatalf("ToSVG(%q) returned error: %v", input, err)
+	}
+	return out.String()
+}
+
+// TestToSVG tests the ToSVG function
+func TestToSVG(t *testing.T) {
+	t.Run("Document", func(t *testing.T) {
+		result := toSVG(t, "hello", glint.SVGOptions{Level: glint.LevelTrue, Title: "demo"})
+		for _, want := range []string{`<svg xmlns="http://www.w3.org/2000/svg"`, `>demo</text>`, `>hello</text>`, "</svg>"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("ToSVG output should contain %q, got:\n%s", want, result)
+			}
+		}
+	})
+
+	t.Run("NoWindow", func(t *testing.T) {
+		result := toSVG(t, "hello", glint.SVGOptions{Level: glint.LevelTrue, NoWindow: true})
+		if strings.Contains(result, "<circle") {
+			t.Errorf("ToSVG with NoWindow should not draw window buttons, got:\n%s", result)
+		}
+	})
+
+	t.Run("Escaping", func(t *testing.T) {
+		result := toSVG(t, "<a & b>", glint.SVGOptions{Level: glint.LevelTrue})
+		if !strings.Contains(result, "&lt;a &amp; b&gt;") || strings.Contains(result, "<a &") {
+			t.Errorf("ToSVG should escape text, got:\n%s", result)
+		}
+	})
+
+	t.Run("Palette", func(t *testing.T) {
+		result := toSVG(t, "\x1b[31mred", glint.SVGOptions{Level: glint.Level16, Palette: glint.PaletteDracula})
+		if !strings.Contains(result, `fill="#ff5555"`) || !strings.Contains(result, `fill="#282a36"`) {
+			t.Errorf("ToSVG should use the palette colors, got:\n%s", result)
+		}
+	})
+
+	t.Run("LevelDownsampling", func(t *testing.T) {
+		input := "\x1b[38;2;255;135;0mx"
+		tests := []struct {
+			level    glint.Level
+			expected string
+		}{
+			{glint.LevelTrue, `fill="#ff8700"`},
+			{glint.Level256, `fill="#ff8700"`},
+			{glint.Level16, `fill="#cdcd00"`},
+			{glint.LevelNone, `fill="#e5e5e5"`},
+		}
+		for _, test := range tests {
+			opts := glint.SVGOptions{Level: test.level, Monochrome: test.level == glint.LevelNone}
+			if result := toSVG(t, input, opts); !strings.Contains(result, test.expected) {
+				t.Errorf("ToSVG at %v should contain %q, got:\n%s", test.level, test.expected, result)
+			}
+		}
+
+		if result := toSVG(t, input, glint.SVGOptions{}); !strings.Contains(result, `fill="#ff8700"`) {
+			t.Errorf("The zero SVGOptions should render full colors like ToHTML, got:\n%s", result)
+		}
+	})
+
+	t.Run("Columns", func(t *testing.T) {
+		result := toSVG(t, "abcdefgh", glint.SVGOptions{Level: glint.LevelTrue, Columns: 4, NoWindow: true})
+		if strings.Count(result, "<text") != 2 {
+			t.Errorf("ToSVG with Columns 4 should wrap 8 cells into 2 rows, got:\n%s", result)
+		}
+	})
+
+	t.Run("Hyperlink", func(t *testing.T) {
+		result := toSVG(t, "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", glint.SVGOptions{Level: glint.LevelTrue})
+		if !strings.Contains(result, `<a href="https://example.com">`) {
+			t.Errorf("ToSVG should render hyperlinks as anchors, got:\n%s", result)
+		}
+	})
+}
+
+// TestColorConvert tests downsampling colors to a color level
+func TestColorConvert(t *testing.T) {
+	rgb := core.Color{Kind: core.ColorRGB, R: 255, G: 135, B: 0}
+	gray := core.Color{Kind: core.ColorRGB, R: 128, G: 128, B: 128}
+	basic := core.Color{Kind: core.ColorANSI, Index: 9}
+
+	tests := []struct {
+		name     string
+		color    core.Color
+		level    core.Level
+		expected core.Color
+	}{
+		{"TrueColorKeepsRGB", rgb, core.LevelTrue, rgb},
+		{"RGBTo256", rgb, core.Level256, core.Color{Kind: core.ColorANSI256, Index: 208}},
+		{"GrayTo256", gray, core.Level256, core.Color{Kind: core.ColorANSI256, Index: 244}},
+		{"RGBTo16", rgb, core.Level16, core.Color{Kind: core.ColorANSI, Index: 3}},
+		{"Low256To16", core.Color{Kind: core.ColorANSI256, Index: 4}, core.Level16, core.Color{Kind: core.ColorANSI, Index: 4}},
+		{"BasicUnchanged", basic, core.Level16, basic},
+		{"NoneDropsColor", basic, core.LevelNone, core.Color{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := test.color.Convert(test.level); result != test.expected {
+				t.Errorf("Convert(%v) should return %+v, got %+v", test.level, test.expected, result)
+			}
+		})
+	}
+}
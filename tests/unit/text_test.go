@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/droqsic/glint"
+)
+
+// TestWidth tests the Width function
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{"Empty", "", 0},
+		{"Plain", "hello", 5},
+		{"Colored", "\x1b[31mhello\x1b[0m", 5},
+		{"Hyperlink", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", 4},
+		{"Wide", "日本", 4},
+		{"Combining", "é", 1},
+		{"EmojiJoined", "👨‍👩", 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := glint.Width(test.input); result != test.expected {
+				t.Errorf("Width(%q) should return %d, got %d", test.input, test.expected, result)
+			}
+		})
+	}
+}
+
+// TestTruncate tests the Truncate function
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		tail     string
+		expected string
+	}{
+		{"Fits", "hello", 5, "…", "hello"},
+		{"Plain", "hello world", 8, "…", "hello w…"},
+		{"Colored", "\x1b[31mhello world\x1b[0m", 6, "...", "\x1b[31mhel...\x1b[0m"},
+		{"StyleEndsBeforeCut", "\x1b[1mhi\x1b[0m there", 5, "", "\x1b[1mhi\x1b[0m th"},
+		{"Hyperlink", "\x1b]8;;https://example.com\x1b\\example\x1b]8;;\x1b\\", 4, "", "\x1b]8;;https://example.com\x1b\\exam\x1b]8;;\x1b\\"},
+		{"WideNotSplit", "日本語", 5, "", "日本"},
+		{"TailWiderThanWidth", "hello", 2, "...", ".."},
+		{"ColoredTailWiderThanWidth", "hello", 1, "\x1b[2m...\x1b[0m", "\x1b[2m.\x1b[0m"},
+		{"ZeroWidth", "hello", 0, "…", ""},
+		{"NegativeWidth", "hello world", -1, "…", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := glint.Truncate(test.input, test.width, test.tail); result != test.expected {
+				t.Errorf("Truncate(%q, %d, %q) should return %q, got %q", test.input, test.width, test.tail, test.expected, result)
+			}
+		})
+	}
+}
+
+// TestPadding tests the PadRight, PadLeft and Center functions
+func TestPadding(t *testing.T) {
+	colored := "\x1b[32mok\x1b[0m"
+
+	if result := glint.PadRight(colored, 5); result != colored+"   " {
+		t.Errorf("PadRight should pad by display width, got %q", result)
+	}
+
+	if result := glint.PadLeft(colored, 5); result != "   "+colored {
+		t.Errorf("PadLeft should pad by display width, got %q", result)
+	}
+
+	if result := glint.Center(colored, 5); result != " "+colored+"  " {
+		t.Errorf("Center should put the extra space on the right, got %q", result)
+	}
+
+	if result := glint.PadRight("日本", 3); result != "日本" {
+		t.Errorf("PadRight should not change text wider than width, got %q", result)
+	}
+}
+
+// TestWrap tests the Wrap function
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		expected string
+	}{
+		{"Fits", "hello world", 20, "hello world"},
+		{"Plain", "the quick brown fox", 10, "the quick\nbrown fox"},
+		{"LongWord", "abcdefghij", 4, "abcd\nefgh\nij"},
+		{"KeepsNewlines", "a b\nc d", 10, "a b\nc d"},
+		{"ZeroWidth", "hello world", 0, "hello world"},
+		{"Colored", "\x1b[31mhello world\x1b[0m", 5, "\x1b[31mhello\x1b[0m\n\x1b[31mworld\x1b[0m"},
+		{"StyleChangeInLine", "\x1b[1mbold\x1b[22m plain text", 10, "\x1b[1mbold\x1b[22m plain\ntext"},
+		{"Hyperlink", "\x1b]8;;https://example.com\x1b\\see the docs\x1b]8;;\x1b\\", 7,
+			"\x1b]8;;https://example.com\x1b\\see the\x1b]8;;\x1b\\\n\x1b]8;;https://example.com\x1b\\docs\x1b]8;;\x1b\\"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := glint.Wrap(test.input, test.width); result != test.expected {
+				t.Errorf("Wrap(%q, %d) should return %q, got %q", test.input, test.width, test.expected, result)
+			}
+		})
+	}
+
+	t.Run("LinesFitWidth", func(t *testing.T) {
+		input := strings.Repeat("\x1b[38;5;208mlorem\x1b[0m ipsum 日本語 ", 20)
+		for _, line := range strings.Split(glint.Wrap(input, 13), "\n") {
+			if w := glint.Width(line); w > 13 {
+				t.Errorf("Wrapped line %q is %d cells wide, want at most 13", line, w)
+			}
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/droqsic/glint"
+	"github.com/droqsic/glint/glinttest"
+)
+
+// TestStyleSequence tests that styles degrade with the color level
+func TestStyleSequence(t *testing.T) {
+	glinttest.Terminal(t, glinttest.Options{Level: glint.LevelTrue, TTY: true, Background: glint.BackgroundDark})
+	style := glint.Style{Color: glint.AdaptiveColor{Light: glint.Hex("#000080"), Dark: glint.Hex("#ff0000")}, Bold: true}
+
+	tests := []struct {
+		level    glint.Level
+		expected string
+	}{
+		{glint.LevelTrue, "\x1b[1;38;2;255;0;0m"},
+		{glint.Level256, "\x1b[1;38;5;196m"},
+		{glint.Level16, "\x1b[1;91m"},
+		{glint.LevelNone, "\x1b[1m"},
+	}
+	for _, test := range tests {
+		if got := style.Sequence(test.level); got != test.expected {
+			t.Errorf("Sequence(%s) should be %q, got %q", glint.LevelName(test.level), test.expected, got)
+		}
+	}
+
+	if got := (glint.Style{}).RenderLevel("plain", glint.LevelTrue); got != "plain" {
+		t.Errorf("The zero style should leave text unchanged, got %q", got)
+	}
+
+	glint.SetBackground(glint.BackgroundLight)
+	if got := style.Sequence(glint.LevelTrue); got != "\x1b[1;38;2;0;0;128m" {
+		t.Errorf("Light backgrounds should use the light variant, got %q", got)
+	}
+}
+
+// TestStyleRender tests rendering for stdout with a fake terminal
+func TestStyleRender(t *testing.T) {
+	t.Cleanup(func() { glint.SetNoColorPolicy(glint.NoColorStripAll) })
+
+	glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, TTY: true, Background: glint.BackgroundDark})
+	glinttest.AssertOutput(t, glint.ThemeDefault.Error.Render("failed"), "<bold bright-red>failed<reset>")
+
+	glinttest.Terminal(t, glinttest.Options{Level: glint.Level256})
+	if got := glint.ThemeDefault.Error.Render("failed"); got != "failed" {
+		t.Errorf("Render should not style text for a pipe, got %q", got)
+	}
+
+	glinttest.Terminal(t, glinttest.Options{Level: glint.Level256, TTY: true, Env: map[string]string{"NO_COLOR": "1"}})
+	glint.SetNoColorPolicy(glint.NoColorColorOnly)
+	glinttest.AssertOutput(t, glint.ThemeDefault.Error.Render("failed"), "<bold>failed<reset>")
+}
+
+// TestThemeNamed tests the built-in themes
+func TestThemeNamed(t *testing.T) {
+	for _, name := range []string{"default", "high-contrast", "solarized", "nord", "dracula", "gruvbox"} {
+		theme, ok := glint.ThemeNamed(name)
+		if !ok || theme.Name != name {
+			t.Errorf("ThemeNamed(%q) should return the theme, got %q, %v", name, theme.Name, ok)
+		}
+		if theme.Error.Color.Dark.IsDefault() || !theme.Error.Bold {
+			t.Errorf("Theme %q should color errors in bold, got %+v", name, theme.Error)
+		}
+	}
+
+	if _, ok := glint.ThemeNamed("rainbow"); ok {
+		t.Errorf("ThemeNamed should reject unknown themes")
+	}
+	if s, ok := glint.ThemeNord.Style("Heading"); !ok || s != glint.ThemeNord.Heading {
+		t.Errorf("Style should look up the semantic names ignoring case, got %+v, %v", s, ok)
+	}
+	if _, ok := glint.ThemeNord.Style("title"); ok {
+		t.Errorf("Style should reject unknown names")
+	}
+}
+
+// TestParseTheme tests loading themes from JSON and TOML
+func TestParseTheme(t *testing.T) {
+	theme, err := glint.LoadTheme(filepath.Join("testdata", "theme.toml"))
+	if err != nil {
+		t.Fatalf("LoadTheme should read the TOML theme: %v", err)
+	}
+	if theme.Name != "ocean" || theme.Success != glint.ThemeNord.Success {
+		t.Errorf("The theme should be named ocean and keep the styles of its base, got %+v", theme)
+	}
+	if want := (glint.Style{Color: glint.AdaptiveColor{Light: glint.Hex("#ff5f5f"), Dark: glint.Hex("#ff5f5f")}, Bold: true, Underline: true}); theme.Error != want {
+		t.Errorf("Error should override the color and keep bold, got %+v", theme.Error)
+	}
+	if want := (glint.AdaptiveColor{Light: glint.ANSI(8), Dark: glint.ANSI(8)}); theme.Muted.Color != want {
+		t.Errorf("Muted should accept palette indexes and color names, got %+v", theme.Muted.Color)
+	}
+
+	json := `{"error": {"light": "red", "dark": 196, "bold": false}, "code": {"color": "default", "italic": true}}`
+	theme, err = glint.ParseThemeJSON([]byte(json))
+	if err != nil {
+		t.Fatalf("ParseThemeJSON should parse the theme: %v", err)
+	}
+	if want := (glint.Style{Color: glint.AdaptiveColor{Light: glint.ANSI(1), Dark: glint.ANSI256(196)}}); theme.Error != want {
+		t.Errorf("Error should have the given colors, got %+v", theme.Error)
+	}
+	if want := (glint.Style{Italic: true}); theme.Code != want || theme.Name != "" {
+		t.Errorf("A theme without base should start empty, got %+v", theme)
+	}
+
+	errors := []struct {
+		toml     string
+		contains string
+	}{
+		{"[title]\nbold = true", `unknown style "title"`},
+		{"[error]\nblink = true", `unknown key "blink"`},
+		{"[error]\ncolor = \"#12345\"", `invalid color "#12345"`},
+		{"[error]\nbold = yes", "line 2: unsupported value"},
+		{"base = \"rainbow\"", `unknown base theme "rainbow"`},
+		{"[error\n", "line 1: malformed table header"},
+		{"name = \"ocean", "line 1: unterminated string"},
+	}
+	for _, test := range errors {
+		if _, err := glint.ParseThemeTOML([]byte(test.toml)); err == nil || !strings.Contains(err.Error(), test.contains) {
+			t.Errorf("ParseThemeTOML(%q) should fail with %q, got %v", test.toml, test.contains, err)
+		}
+	}
+	if _, err := glint.ParseThemeJSON([]byte(`{"error": {"bold": [true]}}`)); err == nil {
+		t.Errorf("ParseThemeJSON should reject arrays")
+	}
+
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := os.WriteFile(path, []byte(`{"name": "mine", "base": "gruvbox"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if theme, err := glint.LoadTheme(path); err != nil || theme.Name != "mine" || theme.Error != glint.ThemeGruvbox.Error {
+		t.Errorf("LoadTheme should read JSON themes, got %+v, %v", theme, err)
+	}
+}
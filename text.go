@@ -0,0 +1,208 @@
+package glint
+
+import (
+	"strings"
+
+	"github.com/droqsic/glint/internal/ansi"
+)
+
+// Width returns the number of terminal cells s occupies when printed.
+// Escape sequences take no space and wide characters such as CJK ideographs and emoji take two cells.
+func Width(s string) int {
+	return ansi.StringWidth(s)
+}
+
+// Truncate shortens s to at most width cells, replacing the removed text with tail.
+// Escape sequences before the cut are kept and any active style or hyperlink is closed after tail,
+// so the result never leaks color into whatever is printed next. If s already fits it is returned unchanged.
+// A width of zero or less returns an empty string.
+func Truncate(s string, width int, tail string) string {
+	if width <= 0 {
+		return ""
+	}
+	if ansi.StringWidth(s) <= width {
+		return s
+	}
+
+	tailWidth := ansi.StringWidth(tail)
+	if tailWidth > width {
+		var b strings.Builder
+		closeState(&b, writeCells(&b, tail, width))
+		tail, tailWidth = b.String(), width
+	}
+
+	var b strings.Builder
+	state := writeCells(&b, s, width-tailWidth)
+	b.WriteString(tail)
+	closeState(&b, state)
+	return b.String()
+}
+
+// writeCells writes the cells of s that fit in limit cells to b and returns the style active after them.
+func writeCells(b *strings.Builder, s string, limit int) ansi.State {
+	cells, _ := ansi.Cells(s)
+
+	var state ansi.State
+	used := 0
+	for _, cell := range cells {
+		if used+cell.Width > limit {
+			break
+		}
+		writeCell(b, &state, cell, true)
+		used += cell.Width
+	}
+	return state
+}
+
+// PadRight appends spaces to s until it occupies width cells.
+func PadRight(s string, width int) string {
+	if n := width - ansi.StringWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// PadLeft prepends spaces to s until it occupies width cells.
+func PadLeft(s string, width int) string {
+	if n := width - ansi.StringWidth(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// Center surrounds s with spaces until it occupies width cells.
+// When the padding cannot be split evenly the extra space goes on the right.
+func Center(s string, width int) string {
+	n := width - ansi.StringWidth(s)
+	if n <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+}
+
+// Wrap breaks s into lines of at most width cells, preferring to break at spaces.
+// Words longer than width are split across lines. Every line ends with the active style and hyperlink
+// closed and the next line reopens them, so each line renders correctly on its own.
+// Existing newlines in s are kept. A width of zero or less returns s unchanged.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	cells, trailing := ansi.Cells(s)
+	w := wrapper{width: width}
+	w.b.Grow(len(s))
+
+	for _, cell := range cells {
+		switch cell.Text {
+		case "\n":
+			w.flushWord()
+			w.flushSpaces()
+			w.writePrefix(cell)
+			w.newline()
+		case " ":
+			w.flushWord()
+			w.spaces = append(w.spaces, cell)
+			w.spacesWidth += cell.Width
+		default:
+			w.word = append(w.word, cell)
+			w.wordWidth += cell.Width
+		}
+	}
+
+	w.flushWord()
+	w.flushSpaces()
+	for _, tok := range trailing {
+		w.b.WriteString(tok.Raw)
+		w.state.Apply(tok)
+	}
+	return w.b.String()
+}
+
+// wrapper holds the state of a single Wrap call.
+type wrapper struct {
+	b           strings.Builder
+	state       ansi.State
+	width       int         // width is the maximum line width in cells
+	lineWidth   int         // lineWidth is the width of the current line
+	word        []ansi.Cell // word holds the cells of the word being collected
+	wordWidth   int         // wordWidth is the width of word
+	spaces      []ansi.Cell // spaces holds the spaces seen before word
+	spacesWidth int         // spacesWidth is the width of spaces
+}
+
+// flushWord writes the pending spaces and word, starting a new line first if they don't fit.
+// Spaces at a line break are dropped, a word wider than a whole line is split.
+func (w *wrapper) flushWord() {
+	if len(w.word) == 0 {
+		return
+	}
+
+	if w.lineWidth > 0 && w.lineWidth+w.spacesWidth+w.wordWidth > w.width {
+		for _, cell := range w.spaces {
+			w.writePrefix(cell)
+		}
+		w.newline()
+	} else {
+		for _, cell := range w.spaces {
+			writeCell(&w.b, &w.state, cell, true)
+			w.lineWidth += cell.Width
+		}
+	}
+
+	for _, cell := range w.word {
+		if w.lineWidth > 0 && w.lineWidth+cell.Width > w.width {
+			w.newline()
+		}
+		writeCell(&w.b, &w.state, cell, true)
+		w.lineWidth += cell.Width
+	}
+
+	w.word, w.wordWidth = w.word[:0], 0
+	w.spaces, w.spacesWidth = w.spaces[:0], 0
+}
+
+// flushSpaces writes the pending spaces that still fit on the current line and drops the rest.
+func (w *wrapper) flushSpaces() {
+	for _, cell := range w.spaces {
+		fits := w.lineWidth+cell.Width <= w.width
+		writeCell(&w.b, &w.state, cell, fits)
+		if fits {
+			w.lineWidth += cell.Width
+		}
+	}
+	w.spaces, w.spacesWidth = w.spaces[:0], 0
+}
+
+// writePrefix writes only the escape sequences of cell.
+func (w *wrapper) writePrefix(cell ansi.Cell) {
+	writeCell(&w.b, &w.state, cell, false)
+}
+
+// newline ends the current line and reopens the active style and hyperlink on the next one.
+func (w *wrapper) newline() {
+	closeState(&w.b, w.state)
+	w.b.WriteByte('\n')
+	w.b.WriteString(w.state.SGR())
+	w.b.WriteString(w.state.OpenLink())
+	w.lineWidth = 0
+}
+
+// writeCell writes the escape sequences of cell, tracking their effect on state, followed by its text if withText is set.
+func writeCell(b *strings.Builder, state *ansi.State, cell ansi.Cell, withText bool) {
+	for _, tok := range cell.Prefix {
+		b.WriteString(tok.Raw)
+		state.Apply(tok)
+	}
+	if withText {
+		b.WriteString(cell.Text)
+	}
+}
+
+// closeState writes the sequences that close the hyperlink and reset the style active in state.
+func closeState(b *strings.Builder, state ansi.State) {
+	b.WriteString(state.CloseLink())
+	if state.Styled() {
+		b.WriteString(ansi.Reset)
+	}
+}
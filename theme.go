@@ -0,0 +1,159 @@
+package glint
+
+import "strings"
+
+// Theme maps the semantic parts of command-line output to styles, so programs sharing a theme color them consistently.
+// Styles use adaptive colors and degrade with the color level, see Style.Render.
+type Theme struct {
+	Name    string // Name identifies the theme
+	Error   Style  // Error marks failures
+	Warning Style  // Warning marks problems that don't stop the program
+	Success Style  // Success marks completed work
+	Info    Style  // Info marks neutral notices
+	Muted   Style  // Muted de-emphasizes secondary text such as timestamps and hints
+	Accent  Style  // Accent highlights names and values
+	Heading Style  // Heading marks section titles
+	Code    Style  // Code marks commands, paths and code
+}
+
+// themeRoles lists the semantic names of the styles of a theme, as used by Theme.Style and theme files.
+var themeRoles = [...]string{"error", "warning", "success", "info", "muted", "accent", "heading", "code"}
+
+// role returns the style of the theme with the given semantic name, or nil if there is none.
+func (t *Theme) role(name string) *Style {
+	switch strings.ToLower(name) {
+	case "error":
+		return &t.Error
+	case "warning":
+		return &t.Warning
+	case "success":
+		return &t.Success
+	case "info":
+		return &t.Info
+	case "muted":
+		return &t.Muted
+	case "accent":
+		return &t.Accent
+	case "heading":
+		return &t.Heading
+	case "code":
+		return &t.Code
+	}
+	return nil
+}
+
+// Style returns the style with the given semantic name: error, warning, success, info, muted, accent, heading or code.
+func (t Theme) Style(name string) (Style, bool) {
+	if s := t.role(name); s != nil {
+		return *s, true
+	}
+	return Style{}, false
+}
+
+// adaptive returns an adaptive color from two hex strings.
+func adaptive(light, dark string) AdaptiveColor {
+	return AdaptiveColor{Light: Hex(light), Dark: Hex(dark)}
+}
+
+// basic returns an adaptive color from two basic color indexes.
+func basic(light, dark uint8) AdaptiveColor {
+	return AdaptiveColor{Light: ANSI(light), Dark: ANSI(dark)}
+}
+
+// ThemeDefault uses the 16 basic colors, so it follows the user's terminal color scheme.
+var ThemeDefault = Theme{
+	Name:    "default",
+	Error:   Style{Color: basic(1, 9), Bold: true},
+	Warning: Style{Color: basic(3, 11)},
+	Success: Style{Color: basic(2, 10)},
+	Info:    Style{Color: basic(4, 12)},
+	Muted:   Style{Dim: true},
+	Accent:  Style{Color: basic(5, 13)},
+	Heading: Style{Bold: true},
+	Code:    Style{Color: basic(6, 14)},
+}
+
+// ThemeHighContrast uses the 16 basic colors with bold text and no dimming, for low vision and bright displays.
+var ThemeHighContrast = Theme{
+	Name:    "high-contrast",
+	Error:   Style{Color: basic(1, 9), Bold: true, Underline: true},
+	Warning: Style{Color: basic(5, 11), Bold: true},
+	Success: Style{Color: basic(2, 10), Bold: true},
+	Info:    Style{Color: basic(4, 14), Bold: true},
+	Muted:   Style{Color: basic(0, 15)},
+	Accent:  Style{Color: basic(0, 11), Bold: true, Underline: true},
+	Heading: Style{Color: basic(0, 15), Bold: true},
+	Code:    Style{Color: basic(4, 14)},
+}
+
+// ThemeSolarized uses the accent colors of Ethan Schoonover's Solarized, which are shared by its light and dark variants.
+var ThemeSolarized = Theme{
+	Name:    "solarized",
+	Error:   Style{Color: adaptive("#dc322f", "#dc322f"), Bold: true},
+	Warning: Style{Color: adaptive("#b58900", "#b58900")},
+	Success: Style{Color: adaptive("#859900", "#859900")},
+	Info:    Style{Color: adaptive("#268bd2", "#268bd2")},
+	Muted:   Style{Color: adaptive("#93a1a1", "#586e75")},
+	Accent:  Style{Color: adaptive("#d33682", "#d33682")},
+	Heading: Style{Color: adaptive("#cb4b16", "#cb4b16"), Bold: true},
+	Code:    Style{Color: adaptive("#2aa198", "#2aa198")},
+}
+
+// ThemeNord uses the Aurora and Frost colors of Nord, with darker Frost shades on light backgrounds.
+var ThemeNord = Theme{
+	Name:    "nord",
+	Error:   Style{Color: adaptive("#bf616a", "#bf616a"), Bold: true},
+	Warning: Style{Color: adaptive("#d08770", "#ebcb8b")},
+	Success: Style{Color: adaptive("#a3be8c", "#a3be8c")},
+	Info:    Style{Color: adaptive("#5e81ac", "#81a1c1")},
+	Muted:   Style{Color: adaptive("#4c566a", "#616e88")},
+	Accent:  Style{Color: adaptive("#5e81ac", "#88c0d0")},
+	Heading: Style{Color: adaptive("#5e81ac", "#8fbcbb"), Bold: true},
+	Code:    Style{Color: adaptive("#b48ead", "#b48ead")},
+}
+
+// ThemeDracula uses the Dracula colors on dark backgrounds and those of its light variant, Alucard, on light ones.
+var ThemeDracula = Theme{
+	Name:    "dracula",
+	Error:   Style{Color: adaptive("#cb3a2a", "#ff5555"), Bold: true},
+	Warning: Style{Color: adaptive("#a34d14", "#ffb86c")},
+	Success: Style{Color: adaptive("#14710a", "#50fa7b")},
+	Info:    Style{Color: adaptive("#036a96", "#8be9fd")},
+	Muted:   Style{Color: adaptive("#6c664b", "#6272a4")},
+	Accent:  Style{Color: adaptive("#a3144d", "#ff79c6")},
+	Heading: Style{Color: adaptive("#644ac9", "#bd93f9"), Bold: true},
+	Code:    Style{Color: adaptive("#846e15", "#f1fa8c")},
+}
+
+// ThemeGruvbox uses the Gruvbox light and dark colors.
+var ThemeGruvbox = Theme{
+	Name:    "gruvbox",
+	Error:   Style{Color: adaptive("#9d0006", "#fb4934"), Bold: true},
+	Warning: Style{Color: adaptive("#b57614", "#fabd2f")},
+	Success: Style{Color: adaptive("#79740e", "#b8bb26")},
+	Info:    Style{Color: adaptive("#076678", "#83a598")},
+	Muted:   Style{Color: adaptive("#7c6f64", "#928374")},
+	Accent:  Style{Color: adaptive("#8f3f71", "#d3869b")},
+	Heading: Style{Color: adaptive("#af3a03", "#fe8019"), Bold: true},
+	Code:    Style{Color: adaptive("#427b58", "#8ec07c")},
+}
+
+// ThemeNamed returns the built-in theme with the given name: "default", "high-contrast", "solarized", "nord",
+// "dracula" or "gruvbox".
+func ThemeNamed(name string) (Theme, bool) {
+	switch strings.ToLower(name) {
+	case "default":
+		return ThemeDefault, true
+	case "high-contrast":
+		return ThemeHighContrast, true
+	case "solarized":
+		return ThemeSolarized, true
+	case "nord":
+		return ThemeNord, true
+	case "dracula":
+		return ThemeDracula, true
+	case "gruvbox":
+		return ThemeGruvbox, true
+	}
+	return Theme{}, false
+}
@@ -0,0 +1,298 @@
+package glint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// themeSpec is a theme file before it is applied: top-level keys, and the keys of each style by semantic name.
+type themeSpec struct {
+	top    map[string]string            // top holds the top-level keys, name and base
+	styles map[string]map[string]string // styles holds the keys of each style
+}
+
+// ParseThemeJSON parses a theme in JSON. The optional "base" names a built-in theme to start from, "name" names the
+// result, and each semantic name holds a style whose keys are all optional:
+//
+//	{
+//	  "name": "ocean",
+//	  "base": "nord",
+//	  "error": {"color": "#ff5f5f", "bold": true},
+//	  "accent": {"light": "#005f87", "dark": "#87d7ff", "underline": true}
+//	}
+//
+// Colors are hex strings such as "#ff5f5f", indexes of the 256 color palette where 0-15 are the basic colors, or the
+// names of the basic colors such as "red" and "bright-red". "color" sets both variants, "light" and "dark" one each.
+// The attributes are "bold", "dim", "italic" and "underline". Styles and keys that are left out keep the base theme's.
+func ParseThemeJSON(data []byte) (Theme, error) {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return Theme{}, fmt.Errorf("theme: %w", err)
+	}
+
+	spec := themeSpec{top: make(map[string]string), styles: make(map[string]map[string]string)}
+	for key, value := range doc {
+		if fields, ok := value.(map[string]any); ok {
+			style := make(map[string]string, len(fields))
+			for field, v := range fields {
+				s, err := jsonScalar(v)
+				if err != nil {
+					return Theme{}, fmt.Errorf("theme: %s.%s: %w", key, field, err)
+				}
+				style[field] = s
+			}
+			spec.styles[key] = style
+			continue
+		}
+
+		s, err := jsonScalar(value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme: %s: %w", key, err)
+		}
+		spec.top[key] = s
+	}
+	return spec.theme()
+}
+
+// jsonScalar converts a JSON string, number or boolean to the text it would have in a TOML theme.
+func jsonScalar(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return "", fmt.Errorf("must be a string, number or boolean")
+}
+
+// ParseThemeTOML parses a theme in TOML, with the keys described for ParseThemeJSON and one table per style:
+//
+//	name = "ocean"
+//	base = "nord"
+//
+//	[error]
+//	color = "#ff5f5f"
+//	bold = true
+//
+// Only the parts of TOML that themes need are supported: comments, tables, and string, integer and boolean values.
+func ParseThemeTOML(data []byte) (Theme, error) {
+	spec := themeSpec{top: make(map[string]string), styles: make(map[string]map[string]string)}
+	section := spec.top
+
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			name, rest, ok := strings.Cut(line[1:], "]")
+			if rest = strings.TrimSpace(rest); !ok || rest != "" && rest[0] != '#' {
+				return Theme{}, fmt.Errorf("theme: line %d: malformed table header", n+1)
+			}
+			name = strings.Trim(strings.TrimSpace(name), `"`)
+			if spec.styles[name] == nil {
+				spec.styles[name] = make(map[string]string)
+			}
+			section = spec.styles[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return Theme{}, fmt.Errorf("theme: line %d: expected key = value", n+1)
+		}
+		v, err := tomlValue(strings.TrimSpace(value))
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme: line %d: %w", n+1, err)
+		}
+		section[strings.Trim(strings.TrimSpace(key), `"`)] = v
+	}
+	return spec.theme()
+}
+
+// tomlValue parses a TOML string, integer or boolean, followed by an optional comment.
+func tomlValue(s string) (string, error) {
+	var value, rest string
+	switch {
+	case strings.HasPrefix(s, `"`):
+		end := 1
+		for end < len(s) && s[end] != '"' {
+			if s[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(s) {
+			return "", fmt.Errorf("unterminated string")
+		}
+		unquoted, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("malformed string %s", s[:end+1])
+		}
+		value, rest = unquoted, s[end+1:]
+	case strings.HasPrefix(s, "'"):
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		value, rest = s[1:end+1], s[end+2:]
+	default:
+		value, rest, _ = strings.Cut(s, "#")
+		value = strings.TrimSpace(value)
+		rest = ""
+		if _, err := strconv.Atoi(value); err != nil && value != "true" && value != "false" {
+			return "", fmt.Errorf("unsupported value %q", value)
+		}
+	}
+
+	if rest = strings.TrimSpace(rest); rest != "" && rest[0] != '#' {
+		return "", fmt.Errorf("unexpected %q after value", rest)
+	}
+	return value, nil
+}
+
+// LoadTheme reads a theme file, in TOML if its name ends in .toml and in JSON otherwise.
+func LoadTheme(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	parse := ParseThemeJSON
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		parse = ParseThemeTOML
+	}
+	t, err := parse(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// theme applies the spec to its base theme.
+func (spec themeSpec) theme() (Theme, error) {
+	var t Theme
+	for key, value := range spec.top {
+		switch key {
+		case "name":
+			t.Name = value
+		case "base":
+		default:
+			return Theme{}, fmt.Errorf("theme: unknown key %q", key)
+		}
+	}
+
+	name := t.Name
+	if base, ok := spec.top["base"]; ok {
+		b, found := ThemeNamed(base)
+		if !found {
+			return Theme{}, fmt.Errorf("theme: unknown base theme %q", base)
+		}
+		t = b
+		if name == "" {
+			name = b.Name
+		}
+	}
+	t.Name = name
+
+	for role, fields := range spec.styles {
+		style := t.role(role)
+		if style == nil {
+			return Theme{}, fmt.Errorf("theme: unknown style %q, must be one of %s", role, strings.Join(themeRoles[:], ", "))
+		}
+		if err := applyStyle(style, fields); err != nil {
+			return Theme{}, fmt.Errorf("theme: %s: %w", role, err)
+		}
+	}
+	return t, nil
+}
+
+// applyStyle sets the keys of a theme file on a style. The color key is applied before light and dark.
+func applyStyle(s *Style, fields map[string]string) error {
+	if value, ok := fields["color"]; ok {
+		c, err := parseThemeColor(value)
+		if err != nil {
+			return err
+		}
+		s.Color = AdaptiveColor{Light: c, Dark: c}
+	}
+
+	for key, value := range fields {
+		var flag *bool
+		switch key {
+		case "color":
+			continue
+		case "light", "dark":
+			c, err := parseThemeColor(value)
+			if err != nil {
+				return err
+			}
+			if key == "light" {
+				s.Color.Light = c
+			} else {
+				s.Color.Dark = c
+			}
+			continue
+		case "bold":
+			flag = &s.Bold
+		case "dim":
+			flag = &s.Dim
+		case "italic":
+			flag = &s.Italic
+		case "underline":
+			flag = &s.Underline
+		default:
+			return fmt.Errorf("unknown key %q", key)
+		}
+
+		on, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false, got %q", key, value)
+		}
+		*flag = on
+	}
+	return nil
+}
+
+// parseThemeColor parses a color of a theme file: a hex string, a palette index, a basic color name or "default".
+func parseThemeColor(s string) (Color, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case name == "" || name == "default":
+		return Color{}, nil
+	case strings.HasPrefix(name, "#"):
+		if c := Hex(name); !c.IsDefault() {
+			return c, nil
+		}
+	default:
+		if n, err := strconv.Atoi(name); err == nil && n >= 0 && n <= 255 {
+			if n < 16 {
+				return ANSI(uint8(n)), nil
+			}
+			return ANSI256(uint8(n)), nil
+		}
+		bright := strings.HasPrefix(name, "bright-")
+		for i, basic := range basicColorNames {
+			if strings.TrimPrefix(name, "bright-") == basic {
+				if bright {
+					i += 8
+				}
+				return ANSI(uint8(i)), nil
+			}
+		}
+	}
+	return Color{}, fmt.Errorf("invalid color %q: must be a hex color, a palette index or a basic color name", s)
+}
+
+// basicColorNames are the names of the eight basic colors, in palette order.
+var basicColorNames = [...]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}